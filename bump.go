@@ -0,0 +1,326 @@
+package mvnparse
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+type VersionSection string
+
+const (
+	VersionInParent     VersionSection = "parent"
+	VersionInDependency VersionSection = "dependency"
+	VersionInManagement VersionSection = "managed dependency"
+	// VersionInImport is a BOM imported in dependency management.
+	VersionInImport VersionSection = "imported BOM"
+)
+
+// VersionLocation is where the version of an artifact is defined: the
+// section of File declaring the artifact when the version is a literal, or
+// the properties of File when Property is set.
+type VersionLocation struct {
+	File     *ProjectFile
+	Section  VersionSection
+	Property string
+	// Profile is the id of the profile holding the declaration, if any.
+	Profile string
+	// BOM is the groupId:artifactId of the imported BOM managing the
+	// artifact when the version to change is the one of the import.
+	BOM string
+}
+
+func (l VersionLocation) String() string {
+	section := string(l.Section)
+	if l.BOM != "" {
+		section += " " + l.BOM
+	}
+	if l.Profile != "" {
+		section += " in profile " + l.Profile
+	}
+	if l.Property != "" {
+		return fmt.Sprintf("%s: property %s (%s)", l.File.Path, l.Property, section)
+	}
+	return fmt.Sprintf("%s: %s", l.File.Path, section)
+}
+
+// LocateVersion finds where the version of groupId:artifactId used by pf is
+// defined: its parent, dependencies and dependency management of pf and its
+// ancestors, profiles included, following properties to the POM that
+// defines them. Properties resolve from pf up, as they do once Maven has
+// merged the parents. A version managed by a BOM is located at the import
+// of the BOM, when repo holds it.
+func LocateVersion(pf *ProjectFile, repo *LocalRepository, groupId, artifactId string) ([]VersionLocation, error) {
+	chain, err := ParentChain(pf, repo)
+	if err != nil {
+		return nil, err
+	}
+	var locations []VersionLocation
+	add := func(f *ProjectFile, l dependencyList, section VersionSection, version, bom string) error {
+		location, err := versionLocation(chain, f, section, version)
+		if err != nil {
+			return fmt.Errorf("%s:%s in %s: %v", groupId, artifactId, f.Path, err)
+		}
+		location.Profile, location.BOM = l.profile, bom
+		for _, existing := range locations {
+			if existing == location {
+				return nil
+			}
+		}
+		locations = append(locations, location)
+		return nil
+	}
+	matches := func(g, a string) bool {
+		return pf.Project.Interpolate(g) == groupId && pf.Project.Interpolate(a) == artifactId
+	}
+
+	key := ""
+	if parent := pf.Project.Parent; parent != nil && parent.GroupId == groupId && parent.ArtifactId == artifactId {
+		if err := add(pf, dependencyList{}, VersionInParent, parent.Version, ""); err != nil {
+			return nil, err
+		}
+		key = groupId + ":" + artifactId
+	}
+	for _, f := range chain {
+		for _, l := range dependencyLists(f.Project) {
+			for _, d := range *l.deps {
+				if !matches(d.GroupId, d.ArtifactId) || (l.managed && d.Version == "") {
+					continue
+				}
+				if key == "" {
+					key = pf.Project.Interpolate(d.ManagementKey())
+				}
+				if d.Version == "" {
+					continue
+				}
+				if err := add(f, l, l.section(d), d.Version, ""); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	if key == "" {
+		return nil, fmt.Errorf("%s:%s is not declared by %s: %w", groupId, artifactId, pf.Path, ErrDependencyNotFound)
+	}
+	if len(locations) == 0 && repo != nil {
+		f, l, d, ok := managingImport(chain, repo, key)
+		if ok {
+			bom := pf.Project.Interpolate(d.GroupId) + ":" + pf.Project.Interpolate(d.ArtifactId)
+			if err := add(f, l, VersionInImport, d.Version, bom); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if len(locations) == 0 {
+		return nil, fmt.Errorf("the version of %s:%s is not defined by %s, its parents or the imported BOMs found in the repository", groupId, artifactId, pf.Path)
+	}
+	return locations, nil
+}
+
+// dependencyList is a dependencies section of a POM: of the project or of a
+// profile, managed or not.
+type dependencyList struct {
+	profile string
+	path    string
+	managed bool
+	deps    *[]Dependency
+}
+
+func (l dependencyList) section(d Dependency) VersionSection {
+	switch {
+	case !l.managed:
+		return VersionInDependency
+	case d.Scope == ScopeImport:
+		return VersionInImport
+	default:
+		return VersionInManagement
+	}
+}
+
+// dependencyLists returns the non empty dependencies sections of p, the
+// ones of the project before the ones of its profiles.
+func dependencyLists(p *Project) []dependencyList {
+	lists := []dependencyList{
+		{"", "/project/dependencies/dependency", false, p.Dependencies},
+		{"", "/project/dependencyManagement/dependencies/dependency", true, managedDependencyList(p.DependencyManagement)},
+	}
+	if p.Profiles != nil {
+		for i, profile := range *p.Profiles {
+			path := fmt.Sprintf("/project/profiles/profile[%d]", i+1)
+			lists = append(lists,
+				dependencyList{profile.Id, path + "/dependencies/dependency", false, profile.Dependencies},
+				dependencyList{profile.Id, path + "/dependencyManagement/dependencies/dependency", true, managedDependencyList(profile.DependencyManagement)})
+		}
+	}
+	var nonEmpty []dependencyList
+	for _, l := range lists {
+		if l.deps != nil {
+			nonEmpty = append(nonEmpty, l)
+		}
+	}
+	return nonEmpty
+}
+
+// managingImport returns the first BOM import of chain, nearest POM first,
+// whose BOM from repo manages key, directly or through BOMs it imports.
+func managingImport(chain []*ProjectFile, repo *LocalRepository, key string) (*ProjectFile, dependencyList, Dependency, bool) {
+	e := &explainer{repo: repo}
+	version := func(d Dependency) string {
+		return d.Version
+	}
+	for _, f := range chain {
+		for _, l := range dependencyLists(f.Project) {
+			if !l.managed {
+				continue
+			}
+			for _, d := range *l.deps {
+				if d.Scope != ScopeImport {
+					continue
+				}
+				bomChain, err := e.bomChain(Coordinates{
+					GroupId:    chain[0].Project.Interpolate(d.GroupId),
+					ArtifactId: chain[0].Project.Interpolate(d.ArtifactId),
+					Version:    e.resolve(chain, d.Version),
+					Type:       "pom",
+				})
+				if err != nil {
+					continue
+				}
+				if _, ok := e.managed(bomChain, key, version, 1); ok {
+					return f, l, d, true
+				}
+			}
+		}
+	}
+	return nil, dependencyList{}, Dependency{}, false
+}
+
+// versionLocation follows version, declared in the section of f, to the
+// property defining it, if any.
+func versionLocation(chain []*ProjectFile, f *ProjectFile, section VersionSection, version string) (VersionLocation, error) {
+	l := VersionLocation{File: f, Section: section}
+	seen := map[string]bool{}
+	for {
+		name, ok := propertyName(strings.TrimSpace(version))
+		if !ok {
+			if strings.Contains(version, "${") {
+				return l, fmt.Errorf("version %q mixes properties and text", version)
+			}
+			return l, nil
+		}
+		if seen[name] {
+			return l, fmt.Errorf("property %s references itself", name)
+		}
+		seen[name] = true
+		owner := chainPropertyOwner(chain, name)
+		if owner == nil {
+			if _, builtin := chain[0].Project.Property(name); builtin {
+				return l, fmt.Errorf("version is the built-in ${%s}", name)
+			}
+			return l, fmt.Errorf("property %s is not defined", name)
+		}
+		l.File, l.Property = owner, name
+		version, _ = owner.Project.Properties.Get(name)
+	}
+}
+
+// chainPropertyOwner returns the nearest POM of chain declaring the property
+// name in its properties section.
+func chainPropertyOwner(chain []*ProjectFile, name string) *ProjectFile {
+	for _, f := range chain {
+		if _, ok := f.Project.Properties.Get(name); ok {
+			return f
+		}
+	}
+	return nil
+}
+
+// UpdateVersion sets the version of groupId:artifactId used by pf at every
+// place LocateVersion finds, updating the property rather than the
+// declaration when the version comes from one, and writes the changed POMs.
+// POMs read from repo are never written: a version defined there fails.
+func UpdateVersion(pf *ProjectFile, repo *LocalRepository, groupId, artifactId, version string) ([]*ProjectFile, error) {
+	locations, err := LocateVersion(pf, repo, groupId, artifactId)
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range locations {
+		if repo != nil && isWithin(repo.Dir, l.File.Path) {
+			return nil, fmt.Errorf("%s: cannot update the local repository", l)
+		}
+	}
+
+	var changed []*ProjectFile
+	for _, l := range locations {
+		if l.setVersion(pf.Project, groupId, artifactId, version) && !containsProjectFile(changed, l.File) {
+			changed = append(changed, l.File)
+		}
+	}
+	for _, f := range changed {
+		if err := f.Write(); err != nil {
+			return nil, err
+		}
+	}
+	return changed, nil
+}
+
+// setVersion applies the new version at l, matching declarations with the
+// properties of the project they are used by, and records the change for
+// Write. It reports whether anything changed.
+func (l VersionLocation) setVersion(user *Project, groupId, artifactId, version string) bool {
+	f := l.File
+	p := f.Project
+	if l.Property != "" {
+		if current, _ := p.Properties.Get(l.Property); current == version {
+			return false
+		}
+		p.SetProperty(l.Property, version)
+		f.setText("/project/properties/"+l.Property, version)
+		return true
+	}
+	if l.Section == VersionInParent {
+		if p.Parent.Version == version {
+			return false
+		}
+		p.Parent.Version = version
+		f.setText("/project/parent/version", version)
+		return true
+	}
+	if l.BOM != "" {
+		i := strings.Index(l.BOM, ":")
+		groupId, artifactId = l.BOM[:i], l.BOM[i+1:]
+	}
+	changed := false
+	for _, list := range dependencyLists(p) {
+		if list.profile != l.Profile {
+			continue
+		}
+		for i := range *list.deps {
+			d := &(*list.deps)[i]
+			literal := d.Version != "" && !strings.Contains(d.Version, "${")
+			if list.section(*d) != l.Section || !literal || d.Version == version {
+				continue
+			}
+			if user.Interpolate(d.GroupId) == groupId && user.Interpolate(d.ArtifactId) == artifactId {
+				d.Version = version
+				f.setText(fmt.Sprintf("%s[%d]/version", list.path, i+1), version)
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+func containsProjectFile(files []*ProjectFile, f *ProjectFile) bool {
+	for _, candidate := range files {
+		if candidate == f {
+			return true
+		}
+	}
+	return false
+}
+
+func isWithin(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,159 @@
+package mvnparse
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocateVersion(t *testing.T) {
+	dir := writeTestReactor(t)
+	defer os.RemoveAll(dir)
+	core, err := ParseFile(filepath.Join(dir, "core"))
+	assert.NoError(t, err)
+
+	locations, err := LocateVersion(core, nil, "com.fasterxml.jackson.core", "jackson-databind")
+	assert.NoError(t, err)
+	assert.Len(t, locations, 1)
+	assert.Equal(t, filepath.Join(dir, "pom.xml"), locations[0].File.Path)
+	assert.Equal(t, VersionInManagement, locations[0].Section)
+	assert.Equal(t, "jackson.version", locations[0].Property)
+
+	locations, err = LocateVersion(core, nil, "com.example", "root")
+	assert.NoError(t, err)
+	assert.Equal(t, []VersionLocation{{File: core, Section: VersionInParent}}, locations)
+
+	// a property of the child overrides the one of the parent
+	core.Project.SetProperty("jackson.version", "2.14.0")
+	locations, err = LocateVersion(core, nil, "com.fasterxml.jackson.core", "jackson-databind")
+	assert.NoError(t, err)
+	assert.Equal(t, core, locations[0].File)
+
+	_, err = LocateVersion(core, nil, "org.slf4j", "slf4j-api")
+	assert.Error(t, err)
+
+	app, err := ParseFile(filepath.Join(dir, "app"))
+	assert.NoError(t, err)
+	_, err = LocateVersion(app, nil, "com.example", "core")
+	assert.EqualError(t, err, "com.example:core in "+app.Path+": version is the built-in ${project.version}")
+}
+
+func TestUpdateVersion(t *testing.T) {
+	dir := writeTestReactor(t)
+	defer os.RemoveAll(dir)
+	core, err := ParseFile(filepath.Join(dir, "core"))
+	assert.NoError(t, err)
+
+	changed, err := UpdateVersion(core, nil, "com.fasterxml.jackson.core", "jackson-databind", "2.16.0")
+	assert.NoError(t, err)
+	assert.Len(t, changed, 1)
+	root, err := Parse(filepath.Join(dir, "pom.xml"))
+	assert.NoError(t, err)
+	v, _ := root.Properties.Get("jackson.version")
+	assert.Equal(t, "2.16.0", v)
+	assert.Equal(t, "${jackson.version}", (*root.DependencyManagement.Dependencies)[1].Version)
+
+	changed, err = UpdateVersion(core, nil, "junit", "junit", "4.13.2")
+	assert.NoError(t, err)
+	assert.Empty(t, changed)
+	changed, err = UpdateVersion(core, nil, "junit", "junit", "4.13.3")
+	assert.NoError(t, err)
+	assert.Equal(t, []*ProjectFile{core}, changed)
+	updated, err := Parse(core.Path)
+	assert.NoError(t, err)
+	d, err := updated.Dependency("junit:junit")
+	assert.NoError(t, err)
+	assert.Equal(t, "4.13.3", d.Version)
+
+	repo := NewLocalRepository(dir)
+	_, err = UpdateVersion(core, repo, "junit", "junit", "4.13.4")
+	assert.Error(t, err)
+}
+
+const testCommentedPom = `<?xml version="1.0" encoding="UTF-8"?>
+<!-- the service, built by CI -->
+<project xmlns="http://maven.apache.org/POM/4.0.0" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance"
+         xsi:schemaLocation="http://maven.apache.org/POM/4.0.0 https://maven.apache.org/xsd/maven-4.0.0.xsd">
+  <modelVersion>4.0.0</modelVersion>
+  <groupId>com.example</groupId>
+  <artifactId>service</artifactId>
+  <version>1.0</version>
+  <description>Parses &lt;project&gt; files &amp; more</description>
+  <properties>
+    <!-- keep in sync with the BOM -->
+    <jackson.version>2.15.2</jackson.version>
+    <guava.version>32.1.3-jre</guava.version>
+  </properties>
+  <dependencies>
+    <dependency>
+      <groupId>com.fasterxml.jackson.core</groupId>
+      <artifactId>jackson-databind</artifactId>
+      <version>${jackson.version}</version>
+    </dependency>
+  </dependencies>
+  <profiles>
+    <profile>
+      <id>legacy</id>
+      <dependencies>
+        <dependency>
+          <groupId>junit</groupId>
+          <artifactId>junit</artifactId>
+          <version>4.12</version><!-- pinned -->
+        </dependency>
+      </dependencies>
+    </profile>
+  </profiles>
+</project>
+`
+
+func TestUpdateVersion_KeepsFormatting(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{"pom.xml": testCommentedPom})
+	defer os.RemoveAll(dir)
+	pf, err := ParseFile(dir)
+	assert.NoError(t, err)
+
+	_, err = UpdateVersion(pf, nil, "com.fasterxml.jackson.core", "jackson-databind", "2.17.0")
+	assert.NoError(t, err)
+	locations, err := LocateVersion(pf, nil, "junit", "junit")
+	assert.NoError(t, err)
+	assert.Equal(t, []VersionLocation{{File: pf, Section: VersionInDependency, Profile: "legacy"}}, locations)
+	_, err = UpdateVersion(pf, nil, "junit", "junit", "4.13.2")
+	assert.NoError(t, err)
+
+	data, err := ioutil.ReadFile(pf.Path)
+	assert.NoError(t, err)
+	expected := strings.Replace(testCommentedPom, "2.15.2", "2.17.0", 1)
+	expected = strings.Replace(expected, "4.12", "4.13.2", 1)
+	assert.Equal(t, expected, string(data))
+}
+
+func TestUpdateVersion_Bom(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{
+		"pom.xml": testBomUserPom,
+		"repository/com/google/guava/guava-bom/32.1.3-jre/guava-bom-32.1.3-jre.pom": testBomPom,
+	})
+	defer os.RemoveAll(dir)
+	repo := NewLocalRepository(filepath.Join(dir, "repository"))
+	pf, err := ParseFile(dir)
+	assert.NoError(t, err)
+
+	locations, err := LocateVersion(pf, repo, "com.google.guava", "guava")
+	assert.NoError(t, err)
+	assert.Equal(t, []VersionLocation{{File: pf, Section: VersionInImport, Property: "guava.version", BOM: "com.google.guava:guava-bom"}}, locations)
+	assert.Equal(t, pf.Path+": property guava.version (imported BOM com.google.guava:guava-bom)", locations[0].String())
+
+	changed, err := UpdateVersion(pf, repo, "com.google.guava", "guava", "33.0.0-jre")
+	assert.NoError(t, err)
+	assert.Equal(t, []*ProjectFile{pf}, changed)
+	data, err := ioutil.ReadFile(pf.Path)
+	assert.NoError(t, err)
+	assert.Equal(t, strings.Replace(testBomUserPom, "32.1.3-jre", "33.0.0-jre", 1), string(data))
+
+	// without the BOM, the version cannot be found
+	_, err = LocateVersion(pf, nil, "com.google.guava", "guava")
+	assert.Error(t, err)
+}
@@ -0,0 +1,109 @@
+package mvnparse
+
+import (
+	"fmt"
+)
+
+// classpathScopes lists the dependency scopes making up each classpath. The
+// provided classpath holds what the runtime environment must supply.
+var classpathScopes = map[string][]string{
+	ScopeCompile:  {ScopeCompile, ScopeProvided, ScopeSystem},
+	ScopeRuntime:  {ScopeCompile, ScopeRuntime},
+	ScopeTest:     {ScopeCompile, ScopeProvided, ScopeSystem, ScopeRuntime, ScopeTest},
+	ScopeProvided: {ScopeProvided, ScopeSystem},
+}
+
+// Classpaths are the classpaths of a project, computed from its resolved
+// dependency graph. The package does not resolve dependencies: the caller
+// builds the graph of a Project under the root NewProjectNode returns, with
+// the effective version and scope of every node.
+type Classpaths struct {
+	Compile  []string
+	Runtime  []string
+	Test     []string
+	Provided []string
+}
+
+// Classpath returns the artifact files of the graph rooted at n, the
+// resolved graph of a project, that belong on the classpath of the given
+// scope, in resolution order. Files are taken
+// from the nodes when set, from <systemPath> for system scope and from repo
+// otherwise. Types not added to the classpath, such as pom, are skipped.
+func (n *DependencyNode) Classpath(scope string, repo *LocalRepository) ([]string, error) {
+	scopes, ok := classpathScopes[scope]
+	if !ok {
+		return nil, fmt.Errorf("no classpath for scope %q", scope)
+	}
+	handlers := DefaultArtifactHandlers
+	if repo != nil {
+		handlers = repo.handlers()
+	}
+
+	var err error
+	files := make([]string, 0)
+	seen := map[string]bool{}
+	for _, child := range n.Children {
+		child.Walk(func(node *DependencyNode, path []*DependencyNode) bool {
+			if err != nil || !node.Included() {
+				return false
+			}
+			if !containsString(scopes, node.Scope()) || !handlers.Get(node.Coordinates().TypeOrDefault()).AddedToClasspath {
+				return true
+			}
+			var file string
+			file, err = node.artifactFile(repo)
+			if err == nil && !seen[file] {
+				seen[file] = true
+				files = append(files, file)
+			}
+			return true
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// Classpaths returns the compile, runtime, test and provided classpaths.
+func (n *DependencyNode) Classpaths(repo *LocalRepository) (*Classpaths, error) {
+	var err error
+	cp := &Classpaths{}
+	for scope, target := range map[string]*[]string{
+		ScopeCompile:  &cp.Compile,
+		ScopeRuntime:  &cp.Runtime,
+		ScopeTest:     &cp.Test,
+		ScopeProvided: &cp.Provided,
+	} {
+		*target, err = n.Classpath(scope, repo)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return cp, nil
+}
+
+func (n *DependencyNode) artifactFile(repo *LocalRepository) (string, error) {
+	switch {
+	case n.File != "":
+		return n.File, nil
+	case n.Scope() == ScopeSystem:
+		if n.Dependency.SystemPath == "" {
+			return "", fmt.Errorf("%s has system scope but no systemPath", n.Coordinates())
+		}
+		return n.Dependency.SystemPath, nil
+	case repo == nil:
+		return "", fmt.Errorf("no file for %s and no local repository", n.Coordinates())
+	default:
+		return repo.Path(n.Coordinates()), nil
+	}
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,43 @@
+package mvnparse
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDependencyNode_Classpath(t *testing.T) {
+	root := testGraph()
+	servlet := testNode("javax.servlet:javax.servlet-api:4.0.1", ScopeProvided)
+	tools := testNode("com.sun:tools:1.8", ScopeSystem)
+	tools.Dependency.SystemPath = "/jdk/lib/tools.jar"
+	bom := testNode("com.example:bom:pom:1.0", ScopeCompile)
+	root.Children = append(root.Children, servlet, tools, bom)
+
+	repo := NewLocalRepository("/repo")
+	path := func(s string) string { return filepath.FromSlash("/repo/" + s) }
+	libA := path("com/example/lib-a/1.0/lib-a-1.0.jar")
+	guava := path("com/google/guava/guava/31.1-jre/guava-31.1-jre.jar")
+	slf4j := path("org/slf4j/slf4j-api/2.0.9/slf4j-api-2.0.9.jar")
+	libB := path("com/example/lib-b/2.0/lib-b-2.0.jar")
+	junit := path("junit/junit/4.13.2/junit-4.13.2.jar")
+	hamcrest := path("org/hamcrest/hamcrest-core/1.3/hamcrest-core-1.3.jar")
+	servletJar := path("javax/servlet/javax.servlet-api/4.0.1/javax.servlet-api-4.0.1.jar")
+
+	cp, err := root.Classpaths(repo)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{libA, guava, slf4j, servletJar, "/jdk/lib/tools.jar"}, cp.Compile)
+	assert.Equal(t, []string{libA, guava, slf4j, libB}, cp.Runtime)
+	assert.Equal(t, []string{libA, guava, slf4j, libB, junit, hamcrest, servletJar, "/jdk/lib/tools.jar"}, cp.Test)
+	assert.Equal(t, []string{servletJar, "/jdk/lib/tools.jar"}, cp.Provided)
+
+	_, err = root.Classpath("import", repo)
+	assert.Error(t, err)
+	_, err = root.Classpath(ScopeCompile, nil)
+	assert.Error(t, err)
+
+	tools.Dependency.SystemPath = ""
+	_, err = root.Classpath(ScopeCompile, repo)
+	assert.Error(t, err)
+}
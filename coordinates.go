@@ -0,0 +1,170 @@
+package mvnparse
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const defaultPluginGroupId = "org.apache.maven.plugins"
+
+// Coordinates identify an artifact. Type is the dependency type (jar,
+// test-jar, pom, ...), which is not always the file extension: use Extension
+// and ArtifactClassifier for the file actually stored in a repository.
+type Coordinates struct {
+	GroupId    string
+	ArtifactId string
+	Version    string
+	Type       string
+	Classifier string
+}
+
+// ParseCoordinates parses the g:a, g:a:v, g:a:p:v and g:a:p:c:v forms. The
+// version of the last two may be empty, as in g:a:jar:tests:, which is how
+// String formats a type or classifier without version.
+func ParseCoordinates(s string) (Coordinates, error) {
+	parts := strings.Split(strings.TrimSpace(s), ":")
+	for i, part := range parts {
+		if part == "" && (i < len(parts)-1 || len(parts) < 4) {
+			return Coordinates{}, fmt.Errorf("invalid coordinates %q", s)
+		}
+	}
+	c := Coordinates{}
+	switch len(parts) {
+	case 2:
+		c.GroupId, c.ArtifactId = parts[0], parts[1]
+	case 3:
+		c.GroupId, c.ArtifactId, c.Version = parts[0], parts[1], parts[2]
+	case 4:
+		c.GroupId, c.ArtifactId, c.Type, c.Version = parts[0], parts[1], parts[2], parts[3]
+	case 5:
+		c.GroupId, c.ArtifactId, c.Type, c.Classifier, c.Version = parts[0], parts[1], parts[2], parts[3], parts[4]
+	default:
+		return Coordinates{}, fmt.Errorf("invalid coordinates %q, expected g:a[:p[:c]]:v", s)
+	}
+	return c, nil
+}
+
+// String formats c in the shortest of the forms accepted by ParseCoordinates,
+// which parses it back to c.
+func (c Coordinates) String() string {
+	switch {
+	case c.Classifier != "":
+		return strings.Join([]string{c.GroupId, c.ArtifactId, c.TypeOrDefault(), c.Classifier, c.Version}, ":")
+	case c.Type != "" && c.Type != "jar":
+		return strings.Join([]string{c.GroupId, c.ArtifactId, c.Type, c.Version}, ":")
+	case c.Version != "":
+		return strings.Join([]string{c.GroupId, c.ArtifactId, c.Version}, ":")
+	default:
+		return c.GroupId + ":" + c.ArtifactId
+	}
+}
+
+// TypeOrDefault returns Type, or jar when it is not set.
+func (c Coordinates) TypeOrDefault() string {
+	if c.Type == "" {
+		return "jar"
+	}
+	return c.Type
+}
+
+// Extension returns the file extension of the artifact for its type, as
+// registered in DefaultArtifactHandlers.
+func (c Coordinates) Extension() string {
+	return c.ExtensionWith(DefaultArtifactHandlers)
+}
+
+// ArtifactClassifier returns the classifier of the artifact file, which is
+// implied by some types (test-jar means the tests classifier).
+func (c Coordinates) ArtifactClassifier() string {
+	return c.ArtifactClassifierWith(DefaultArtifactHandlers)
+}
+
+func (c Coordinates) ExtensionWith(handlers *ArtifactHandlers) string {
+	return handlers.Get(c.TypeOrDefault()).Extension
+}
+
+func (c Coordinates) ArtifactClassifierWith(handlers *ArtifactHandlers) string {
+	if c.Classifier != "" {
+		return c.Classifier
+	}
+	return handlers.Get(c.TypeOrDefault()).Classifier
+}
+
+func (d Dependency) Coordinates() Coordinates {
+	return Coordinates{
+		GroupId:    d.GroupId,
+		ArtifactId: d.ArtifactId,
+		Version:    d.Version,
+		Type:       d.Type,
+		Classifier: d.Classifier,
+	}
+}
+
+func (p Parent) Coordinates() Coordinates {
+	return Coordinates{GroupId: p.GroupId, ArtifactId: p.ArtifactId, Version: p.Version, Type: "pom"}
+}
+
+func (p Plugin) Coordinates() Coordinates {
+	groupId := p.GroupId
+	if groupId == "" {
+		groupId = defaultPluginGroupId
+	}
+	return Coordinates{GroupId: groupId, ArtifactId: p.ArtifactId, Version: p.Version, Type: "maven-plugin"}
+}
+
+func (e Extension) Coordinates() Coordinates {
+	return Coordinates{GroupId: e.GroupId, ArtifactId: e.ArtifactId, Version: e.Version}
+}
+
+func (r Relocation) Coordinates() Coordinates {
+	return Coordinates{GroupId: r.GroupId, ArtifactId: r.ArtifactId, Version: r.Version}
+}
+
+// ToDependency keeps Type and Classifier as they are: g:a:test-jar:v stays a
+// test-jar dependency rather than a jar with the tests classifier.
+func (c Coordinates) ToDependency() Dependency {
+	return Dependency{
+		GroupId:    c.GroupId,
+		ArtifactId: c.ArtifactId,
+		Version:    c.Version,
+		Type:       c.Type,
+		Classifier: c.Classifier,
+	}
+}
+
+func (c Coordinates) ToParent() Parent {
+	return Parent{GroupId: c.GroupId, ArtifactId: c.ArtifactId, Version: c.Version}
+}
+
+func (c Coordinates) ToPlugin() Plugin {
+	return Plugin{GroupId: c.GroupId, ArtifactId: c.ArtifactId, Version: c.Version}
+}
+
+func (c Coordinates) ToExtension() Extension {
+	return Extension{GroupId: c.GroupId, ArtifactId: c.ArtifactId, Version: c.Version}
+}
+
+func (c Coordinates) ToRelocation() Relocation {
+	return Relocation{GroupId: c.GroupId, ArtifactId: c.ArtifactId, Version: c.Version}
+}
+
+// PackageURL returns the purl of the artifact,
+// pkg:maven/group/artifact@version?classifier=...&type=...
+func (c Coordinates) PackageURL() string {
+	purl := "pkg:maven/" + url.PathEscape(c.GroupId) + "/" + url.PathEscape(c.ArtifactId)
+	if c.Version != "" {
+		purl += "@" + url.PathEscape(c.Version)
+	}
+	var qualifiers []string
+	if c.Classifier != "" {
+		qualifiers = append(qualifiers, "classifier="+url.QueryEscape(c.Classifier))
+	}
+	if t := c.TypeOrDefault(); t != "jar" {
+		qualifiers = append(qualifiers, "type="+url.QueryEscape(t))
+	}
+	if len(qualifiers) > 0 {
+		purl += "?" + strings.Join(qualifiers, "&")
+	}
+	return purl
+}
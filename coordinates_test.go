@@ -0,0 +1,88 @@
+package mvnparse
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCoordinates(t *testing.T) {
+	c, err := ParseCoordinates("com.example:lib")
+	assert.NoError(t, err)
+	assert.Equal(t, Coordinates{GroupId: "com.example", ArtifactId: "lib"}, c)
+
+	c, err = ParseCoordinates("com.example:lib:1.0")
+	assert.NoError(t, err)
+	assert.Equal(t, "1.0", c.Version)
+
+	c, err = ParseCoordinates("com.example:lib:war:1.0")
+	assert.NoError(t, err)
+	assert.Equal(t, "war", c.Type)
+	assert.Equal(t, "1.0", c.Version)
+
+	c, err = ParseCoordinates("com.example:lib:jar:sources:1.0")
+	assert.NoError(t, err)
+	assert.Equal(t, "sources", c.Classifier)
+	assert.Equal(t, "1.0", c.Version)
+
+	c, err = ParseCoordinates("com.example:lib:jar:tests:")
+	assert.NoError(t, err)
+	assert.Equal(t, Coordinates{GroupId: "com.example", ArtifactId: "lib", Type: "jar", Classifier: "tests"}, c)
+
+	for _, s := range []string{"lib", "a:b:c:d:e:f", "a::1.0", "", "a:b:", "a:b:war::1.0"} {
+		_, err = ParseCoordinates(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestCoordinates_String(t *testing.T) {
+	for _, s := range []string{"g:a", "g:a:1.0", "g:a:war:1.0", "g:a:jar:sources:1.0", "g:a:test-jar:1.0"} {
+		c, err := ParseCoordinates(s)
+		assert.NoError(t, err)
+		assert.Equal(t, s, c.String())
+	}
+	assert.Equal(t, "g:a:1.0", Coordinates{GroupId: "g", ArtifactId: "a", Version: "1.0", Type: "jar"}.String())
+
+	for _, c := range []Coordinates{
+		{GroupId: "g", ArtifactId: "a"},
+		{GroupId: "g", ArtifactId: "a", Version: "1.0"},
+		{GroupId: "g", ArtifactId: "a", Type: "war"},
+		{GroupId: "g", ArtifactId: "a", Type: "jar", Classifier: "tests"},
+		{GroupId: "g", ArtifactId: "a", Type: "test-jar", Classifier: "tests", Version: "1.0"},
+	} {
+		parsed, err := ParseCoordinates(c.String())
+		assert.NoError(t, err, c.String())
+		assert.Equal(t, c, parsed, c.String())
+	}
+	assert.Equal(t, "g:a:jar:tests:", Coordinates{GroupId: "g", ArtifactId: "a", Classifier: "tests"}.String())
+}
+
+func TestCoordinates_Extension(t *testing.T) {
+	d := Dependency{GroupId: "g", ArtifactId: "a", Version: "1.0", Type: "test-jar"}
+	c := d.Coordinates()
+	assert.Equal(t, "jar", c.Extension())
+	assert.Equal(t, "tests", c.ArtifactClassifier())
+	assert.Equal(t, d, c.ToDependency())
+
+	c = Coordinates{GroupId: "g", ArtifactId: "a", Version: "1.0"}
+	assert.Equal(t, "jar", c.Extension())
+	assert.Equal(t, "", c.ArtifactClassifier())
+
+	c.Type = "pom"
+	assert.Equal(t, "pom", c.Extension())
+}
+
+func TestCoordinates_Conversions(t *testing.T) {
+	plugin := Plugin{ArtifactId: "maven-compiler-plugin", Version: "3.11.0"}
+	assert.Equal(t, "org.apache.maven.plugins:maven-compiler-plugin:maven-plugin:3.11.0", plugin.Coordinates().String())
+
+	parent := Parent{GroupId: "g", ArtifactId: "parent", Version: "1", RelativePath: "../pom.xml"}
+	assert.Equal(t, "pom", parent.Coordinates().Type)
+	assert.Equal(t, Parent{GroupId: "g", ArtifactId: "parent", Version: "1"}, parent.Coordinates().ToParent())
+
+	ext := Extension{GroupId: "g", ArtifactId: "wagon", Version: "2"}
+	assert.Equal(t, ext, ext.Coordinates().ToExtension())
+
+	relocation := Relocation{GroupId: "g", ArtifactId: "new", Version: "2", Message: "moved"}
+	assert.Equal(t, Relocation{GroupId: "g", ArtifactId: "new", Version: "2"}, relocation.Coordinates().ToRelocation())
+}
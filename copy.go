@@ -0,0 +1,162 @@
+package mvnparse
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// copyScopes lists the scopes selected by includeScope and excludeScope, as
+// in the maven-dependency-plugin.
+var copyScopes = map[string][]string{
+	ScopeCompile:  {ScopeCompile, ScopeProvided, ScopeSystem},
+	ScopeRuntime:  {ScopeCompile, ScopeRuntime},
+	ScopeTest:     {ScopeCompile, ScopeProvided, ScopeSystem, ScopeRuntime, ScopeTest},
+	ScopeProvided: {ScopeProvided},
+	ScopeSystem:   {ScopeSystem},
+}
+
+// CopyOptions mirror the parameters of dependency:copy-dependencies.
+type CopyOptions struct {
+	OutputDir string
+	// HardLink links the files instead of copying them, falling back to a
+	// copy when linking fails, e.g. across devices.
+	HardLink        bool
+	StripVersion    bool
+	StripClassifier bool
+	PrependGroupId  bool
+	// IncludeScope and ExcludeScope select dependencies by scope, runtime
+	// meaning compile and runtime scopes for instance.
+	IncludeScope string
+	ExcludeScope string
+	// Includes and Excludes are artifact patterns in the form
+	// groupId[:artifactId[:version[:type[:scope[:classifier]]]]].
+	Includes          []string
+	Excludes          []string
+	ExcludeTransitive bool
+}
+
+// CopyDependencies copies the resolved artifacts of the graph rooted at n
+// into opts.OutputDir and returns the files written. It fails without
+// copying anything when two artifacts would get the same file name.
+func (n *DependencyNode) CopyDependencies(repo *LocalRepository, opts CopyOptions) ([]string, error) {
+	if opts.OutputDir == "" {
+		return nil, errors.New("no output directory")
+	}
+	var includeScopes, excludeScopes []string
+	if opts.IncludeScope != "" {
+		includeScopes = copyScopes[opts.IncludeScope]
+		if includeScopes == nil {
+			return nil, fmt.Errorf("invalid include scope %q", opts.IncludeScope)
+		}
+	}
+	if opts.ExcludeScope != "" {
+		if opts.ExcludeScope == ScopeTest {
+			return nil, errors.New("excluding test scope would exclude every dependency")
+		}
+		excludeScopes = copyScopes[opts.ExcludeScope]
+		if excludeScopes == nil {
+			return nil, fmt.Errorf("invalid exclude scope %q", opts.ExcludeScope)
+		}
+	}
+
+	var selected []*DependencyNode
+	seen := map[string]bool{}
+	n.Walk(func(node *DependencyNode, path []*DependencyNode) bool {
+		if !node.Included() {
+			return false
+		}
+		if len(path) == 1 {
+			return true
+		}
+		d := node.Dependency
+		key := node.Coordinates().String()
+		switch {
+		case seen[key]:
+		case includeScopes != nil && !containsString(includeScopes, node.Scope()):
+		case containsString(excludeScopes, node.Scope()):
+		case len(opts.Includes) > 0 && !matchAnyArtifact(opts.Includes, d):
+		case matchAnyArtifact(opts.Excludes, d):
+		default:
+			seen[key] = true
+			selected = append(selected, node)
+		}
+		return !opts.ExcludeTransitive
+	})
+
+	// stripping versions or classifiers may map two artifacts to one file
+	names := make([]string, len(selected))
+	owners := map[string]*DependencyNode{}
+	for i, node := range selected {
+		names[i] = opts.fileName(node, repo)
+		if other, ok := owners[names[i]]; ok {
+			return nil, fmt.Errorf("%s and %s would both be copied to %s", other.Coordinates(), node.Coordinates(), names[i])
+		}
+		owners[names[i]] = node
+	}
+
+	err := os.MkdirAll(opts.OutputDir, 0755)
+	if err != nil {
+		return nil, err
+	}
+	files := make([]string, 0, len(selected))
+	for i, node := range selected {
+		src, err := node.artifactFile(repo)
+		if err != nil {
+			return nil, err
+		}
+		dst := filepath.Join(opts.OutputDir, names[i])
+		err = linkOrCopy(src, dst, opts.HardLink)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, dst)
+	}
+	return files, nil
+}
+
+func (opts CopyOptions) fileName(node *DependencyNode, repo *LocalRepository) string {
+	c := node.Coordinates()
+	handlers := DefaultArtifactHandlers
+	if repo != nil {
+		handlers = repo.handlers()
+	}
+	name := c.ArtifactId
+	if opts.PrependGroupId {
+		name = c.GroupId + "." + name
+	}
+	if !opts.StripVersion {
+		name += "-" + SnapshotBaseVersion(c.Version)
+	}
+	if classifier := c.ArtifactClassifierWith(handlers); classifier != "" && !opts.StripClassifier {
+		name += "-" + classifier
+	}
+	return name + "." + c.ExtensionWith(handlers)
+}
+
+func linkOrCopy(src, dst string, hardLink bool) error {
+	err := os.Remove(dst)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if hardLink && os.Link(src, dst) == nil {
+		return nil
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(out, in)
+	if err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,104 @@
+package mvnparse
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// writeTestRepository writes a jar for every included node of testGraph into
+// a temporary local repository, holding the artifact coordinates as content.
+func writeTestRepository(t *testing.T) (string, *LocalRepository) {
+	dir := writeTestFiles(t, map[string]string{})
+	repo := NewLocalRepository(dir)
+	testGraph().Walk(func(node *DependencyNode, path []*DependencyNode) bool {
+		if !node.Included() {
+			return false
+		}
+		file := repo.Path(node.Coordinates())
+		assert.NoError(t, os.MkdirAll(filepath.Dir(file), 0755))
+		assert.NoError(t, ioutil.WriteFile(file, []byte(node.Coordinates().String()), 0644))
+		return true
+	})
+	return dir, repo
+}
+
+func copiedNames(files []string) []string {
+	names := make([]string, 0, len(files))
+	for _, f := range files {
+		names = append(names, filepath.Base(f))
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestDependencyNode_CopyDependencies(t *testing.T) {
+	dir, repo := writeTestRepository(t)
+	defer os.RemoveAll(dir)
+	out := filepath.Join(dir, "out")
+
+	files, err := testGraph().CopyDependencies(repo, CopyOptions{OutputDir: out, IncludeScope: ScopeRuntime})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"guava-31.1-jre.jar", "lib-a-1.0.jar", "lib-b-2.0.jar", "slf4j-api-2.0.9.jar"}, copiedNames(files))
+	data, err := ioutil.ReadFile(filepath.Join(out, "guava-31.1-jre.jar"))
+	assert.NoError(t, err)
+	assert.Equal(t, "com.google.guava:guava:31.1-jre", string(data))
+
+	files, err = testGraph().CopyDependencies(repo, CopyOptions{
+		OutputDir:      filepath.Join(dir, "linked"),
+		HardLink:       true,
+		StripVersion:   true,
+		PrependGroupId: true,
+		Includes:       []string{"com.example", "junit:*:*:*:test"},
+		Excludes:       []string{"*:lib-b"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"com.example.lib-a.jar", "junit.junit.jar"}, copiedNames(files))
+
+	files, err = testGraph().CopyDependencies(repo, CopyOptions{OutputDir: out, ExcludeTransitive: true, ExcludeScope: ScopeRuntime})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"junit-4.13.2.jar"}, copiedNames(files))
+
+	_, err = testGraph().CopyDependencies(repo, CopyOptions{OutputDir: out, ExcludeScope: ScopeTest})
+	assert.Error(t, err)
+	_, err = testGraph().CopyDependencies(repo, CopyOptions{})
+	assert.Error(t, err)
+}
+
+func TestDependencyNode_CopyDependencies_Collisions(t *testing.T) {
+	dir, repo := writeTestRepository(t)
+	defer os.RemoveAll(dir)
+	out := filepath.Join(dir, "out")
+
+	root := testNode("com.example:app:1.0", "",
+		testNode("org.one:util:1.0", ScopeCompile),
+		testNode("org.two:util:1.0", ScopeCompile),
+		testNode("org.one:core:1.0", ScopeCompile),
+		testNode("org.one:core:jar:tests:1.0", ScopeTest),
+	)
+	_, err := root.CopyDependencies(repo, CopyOptions{OutputDir: out})
+	assert.EqualError(t, err, "org.one:util:1.0 and org.two:util:1.0 would both be copied to util-1.0.jar")
+	_, err = root.CopyDependencies(repo, CopyOptions{OutputDir: out, PrependGroupId: true, StripClassifier: true})
+	assert.EqualError(t, err, "org.one:core:1.0 and org.one:core:jar:tests:1.0 would both be copied to org.one.core-1.0.jar")
+	_, err = os.Stat(out)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestMatchArtifact(t *testing.T) {
+	d := Dependency{GroupId: "org.example", ArtifactId: "lib", Version: "1.0", Classifier: "tests", Scope: ScopeTest}
+	assert.True(t, matchArtifact("org.example", d))
+	assert.True(t, matchArtifact("org.*:lib:1.*", d))
+	assert.True(t, matchArtifact("*:*:*:jar:test:tests", d))
+	assert.False(t, matchArtifact("*:*:*:jar:compile", d))
+	assert.False(t, matchArtifact("org.example:other", d))
+	assert.False(t, matchArtifact("a:b:c:d:e:f:g", d))
+	assert.True(t, matchArtifact("org.example:lib:[1.0,2.0)", d))
+	assert.False(t, matchArtifact("org.example:lib:(,1.0)", d))
+	assert.False(t, matchArtifact("org.example:lib:[2.0,1.0]", d))
+	assert.Error(t, checkArtifactPattern("org.example:lib:[2.0,1.0]"))
+	assert.NoError(t, checkArtifactPattern("org.example:lib:1.*"))
+}
@@ -0,0 +1,321 @@
+package mvnparse
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+const cycloneDXSpecVersion = "1.5"
+
+// CycloneDXBOM is a CycloneDX 1.5 document, marshalled to either JSON or
+// XML.
+type CycloneDXBOM struct {
+	XMLName      xml.Name              `json:"-" xml:"http://cyclonedx.org/schema/bom/1.5 bom"`
+	BOMFormat    string                `json:"bomFormat" xml:"-"`
+	SpecVersion  string                `json:"specVersion" xml:"-"`
+	SerialNumber string                `json:"serialNumber" xml:"serialNumber,attr"`
+	Version      int                   `json:"version" xml:"version,attr"`
+	Metadata     *CycloneDXMetadata    `json:"metadata,omitempty" xml:"metadata,omitempty"`
+	Components   []CycloneDXComponent  `json:"components" xml:"components>component"`
+	Dependencies []CycloneDXDependency `json:"dependencies" xml:"dependencies>dependency"`
+}
+
+type CycloneDXMetadata struct {
+	Timestamp string                 `json:"timestamp" xml:"timestamp"`
+	Authors   []CycloneDXContact     `json:"authors,omitempty" xml:"authors>author,omitempty"`
+	Component *CycloneDXComponent    `json:"component,omitempty" xml:"component,omitempty"`
+	Supplier  *CycloneDXOrganization `json:"supplier,omitempty" xml:"supplier,omitempty"`
+}
+
+type CycloneDXContact struct {
+	Name  string `json:"name,omitempty" xml:"name,omitempty"`
+	Email string `json:"email,omitempty" xml:"email,omitempty"`
+}
+
+type CycloneDXOrganization struct {
+	Name string   `json:"name,omitempty" xml:"name,omitempty"`
+	URL  []string `json:"url,omitempty" xml:"url,omitempty"`
+}
+
+type CycloneDXComponent struct {
+	Type               string                      `json:"type" xml:"type,attr"`
+	BOMRef             string                      `json:"bom-ref" xml:"bom-ref,attr"`
+	Group              string                      `json:"group,omitempty" xml:"group,omitempty"`
+	Name               string                      `json:"name" xml:"name"`
+	Version            string                      `json:"version,omitempty" xml:"version,omitempty"`
+	Description        string                      `json:"description,omitempty" xml:"description,omitempty"`
+	Scope              string                      `json:"scope,omitempty" xml:"scope,omitempty"`
+	Hashes             CycloneDXHashes             `json:"hashes,omitempty" xml:"hashes,omitempty"`
+	Licenses           CycloneDXLicenses           `json:"licenses,omitempty" xml:"licenses,omitempty"`
+	Purl               string                      `json:"purl,omitempty" xml:"purl,omitempty"`
+	ExternalReferences CycloneDXExternalReferences `json:"externalReferences,omitempty" xml:"externalReferences,omitempty"`
+}
+
+type CycloneDXHash struct {
+	Alg     string `json:"alg" xml:"alg,attr"`
+	Content string `json:"content" xml:",chardata"`
+}
+
+type CycloneDXHashes []CycloneDXHash
+
+func (h CycloneDXHashes) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if len(h) == 0 {
+		return nil
+	}
+	return e.EncodeElement(struct {
+		Hash []CycloneDXHash `xml:"hash"`
+	}{h}, start)
+}
+
+type CycloneDXLicense struct {
+	ID   string `json:"id,omitempty" xml:"id,omitempty"`
+	Name string `json:"name,omitempty" xml:"name,omitempty"`
+	URL  string `json:"url,omitempty" xml:"url,omitempty"`
+}
+
+type CycloneDXLicenseChoice struct {
+	License CycloneDXLicense `json:"license"`
+}
+
+// CycloneDXLicenses is a list of {"license": {...}} objects in JSON and of
+// <license> elements in XML.
+type CycloneDXLicenses []CycloneDXLicenseChoice
+
+func (l CycloneDXLicenses) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if len(l) == 0 {
+		return nil
+	}
+	licenses := make([]CycloneDXLicense, 0, len(l))
+	for _, choice := range l {
+		licenses = append(licenses, choice.License)
+	}
+	return e.EncodeElement(struct {
+		License []CycloneDXLicense `xml:"license"`
+	}{licenses}, start)
+}
+
+type CycloneDXExternalReference struct {
+	Type string `json:"type" xml:"type,attr"`
+	URL  string `json:"url" xml:"url"`
+}
+
+type CycloneDXExternalReferences []CycloneDXExternalReference
+
+func (r CycloneDXExternalReferences) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if len(r) == 0 {
+		return nil
+	}
+	return e.EncodeElement(struct {
+		Reference []CycloneDXExternalReference `xml:"reference"`
+	}{r}, start)
+}
+
+type CycloneDXDependency struct {
+	Ref       string   `json:"ref"`
+	DependsOn []string `json:"dependsOn"`
+}
+
+// MarshalXML writes <dependency ref="..."> with a nested <dependency> per
+// dependsOn entry, as the XML schema nests them.
+func (d CycloneDXDependency) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type ref struct {
+		Ref string `xml:"ref,attr"`
+	}
+	refs := make([]ref, 0, len(d.DependsOn))
+	for _, r := range d.DependsOn {
+		refs = append(refs, ref{r})
+	}
+	return e.EncodeElement(struct {
+		Ref       string `xml:"ref,attr"`
+		DependsOn []ref  `xml:"dependency"`
+	}{d.Ref, refs}, start)
+}
+
+// NewCycloneDX builds a CycloneDX SBOM of the graph rooted at n. POMs and
+// artifact files missing from the nodes are read from repo, which may be nil.
+func NewCycloneDX(n *DependencyNode, repo *LocalRepository) (*CycloneDXBOM, error) {
+	serial, err := newUUID()
+	if err != nil {
+		return nil, err
+	}
+	root := cycloneDXComponent(n, n.loadProject(repo), n.licenses(repo), "application")
+	bom := &CycloneDXBOM{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  cycloneDXSpecVersion,
+		SerialNumber: "urn:uuid:" + serial,
+		Version:      1,
+		Metadata: &CycloneDXMetadata{
+			Timestamp: time.Now().UTC().Format(time.RFC3339),
+			Component: &root,
+		},
+		Components:   []CycloneDXComponent{},
+		Dependencies: []CycloneDXDependency{},
+	}
+	if p := n.loadProject(repo); p != nil {
+		if p.Organization != nil {
+			bom.Metadata.Supplier = &CycloneDXOrganization{Name: p.Organization.Name}
+			if p.Organization.URL != "" {
+				bom.Metadata.Supplier.URL = []string{p.Organization.URL}
+			}
+		}
+		if p.Developers != nil {
+			for _, dev := range *p.Developers {
+				bom.Metadata.Authors = append(bom.Metadata.Authors, CycloneDXContact{Name: dev.Name, Email: dev.Email})
+			}
+		}
+	}
+
+	dependencies := map[string]int{}
+	addDependency := func(ref string) int {
+		i, ok := dependencies[ref]
+		if !ok {
+			i = len(bom.Dependencies)
+			dependencies[ref] = i
+			bom.Dependencies = append(bom.Dependencies, CycloneDXDependency{Ref: ref, DependsOn: []string{}})
+		}
+		return i
+	}
+	addDependency(root.BOMRef)
+	components := map[string]bool{}
+
+	var walkErr error
+	n.Walk(func(node *DependencyNode, path []*DependencyNode) bool {
+		if walkErr != nil || !node.Included() {
+			return false
+		}
+		if len(path) == 1 {
+			return true
+		}
+		ref := node.Coordinates().PackageURL()
+		parent := addDependency(path[len(path)-2].Coordinates().PackageURL())
+		if !containsString(bom.Dependencies[parent].DependsOn, ref) {
+			bom.Dependencies[parent].DependsOn = append(bom.Dependencies[parent].DependsOn, ref)
+		}
+		addDependency(ref)
+		if components[ref] {
+			return true
+		}
+		components[ref] = true
+
+		component := cycloneDXComponent(node, node.loadProject(repo), node.licenses(repo), "library")
+		component.Scope = "required"
+		if node.Optional() || node.Scope() == ScopeTest || node.Scope() == ScopeProvided {
+			component.Scope = "optional"
+		}
+		if file, err := node.artifactFile(repo); err == nil {
+			component.Hashes, err = fileHashes(file)
+			if err != nil && !os.IsNotExist(err) {
+				walkErr = err
+				return false
+			}
+		}
+		bom.Components = append(bom.Components, component)
+		return true
+	})
+	if walkErr != nil {
+		return nil, walkErr
+	}
+	return bom, nil
+}
+
+// cycloneDXComponent describes n. Licenses reliably mapped to a single SPDX
+// license are given by id, others by name: the declared one, else the
+// guessed id or the URL, as CycloneDX requires an id or a name.
+func cycloneDXComponent(n *DependencyNode, p *Project, licenses []LicenseMatch, typ string) CycloneDXComponent {
+	c := n.Coordinates()
+	component := CycloneDXComponent{
+		Type:    typ,
+		BOMRef:  c.PackageURL(),
+		Group:   c.GroupId,
+		Name:    c.ArtifactId,
+		Version: c.Version,
+		Purl:    c.PackageURL(),
+	}
+	for _, m := range licenses {
+		license := CycloneDXLicense{Name: m.License.Name, URL: m.License.URL}
+		switch {
+		case m.Confidence >= LicenseHigh && !strings.Contains(m.ID, " "):
+			license.ID, license.Name = m.ID, ""
+		case license.Name == "" && m.ID != "":
+			license.Name = m.ID
+		case license.Name == "":
+			license.Name = license.URL
+		}
+		if license.ID == "" && license.Name == "" {
+			continue
+		}
+		component.Licenses = append(component.Licenses, CycloneDXLicenseChoice{License: license})
+	}
+	if p == nil {
+		return component
+	}
+	component.Description = p.Description
+	if p.URL != "" {
+		component.ExternalReferences = append(component.ExternalReferences, CycloneDXExternalReference{Type: "website", URL: p.URL})
+	}
+	if p.SCM != nil && p.SCM.URL != "" {
+		component.ExternalReferences = append(component.ExternalReferences, CycloneDXExternalReference{Type: "vcs", URL: p.SCM.URL})
+	}
+	if p.IssueManagement != nil && p.IssueManagement.URL != "" {
+		component.ExternalReferences = append(component.ExternalReferences, CycloneDXExternalReference{Type: "issue-tracker", URL: p.IssueManagement.URL})
+	}
+	return component
+}
+
+func fileHashes(path string) (CycloneDXHashes, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	algs := []string{"MD5", "SHA-1", "SHA-256", "SHA-512"}
+	hashes := []hash.Hash{md5.New(), sha1.New(), sha256.New(), sha512.New()}
+	writers := make([]io.Writer, 0, len(hashes))
+	for _, h := range hashes {
+		writers = append(writers, h)
+	}
+	_, err = io.Copy(io.MultiWriter(writers...), f)
+	if err != nil {
+		return nil, err
+	}
+	result := make(CycloneDXHashes, 0, len(hashes))
+	for i, h := range hashes {
+		result = append(result, CycloneDXHash{Alg: algs[i], Content: hex.EncodeToString(h.Sum(nil))})
+	}
+	return result, nil
+}
+
+func newUUID() (string, error) {
+	b := make([]byte, 16)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
+
+func (b *CycloneDXBOM) ToJSON() ([]byte, error) {
+	return json.MarshalIndent(b, "", "  ")
+}
+
+func (b *CycloneDXBOM) ToXMLStr() (string, error) {
+	data, err := xml.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return xml.Header + string(data), nil
+}
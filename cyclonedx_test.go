@@ -0,0 +1,111 @@
+package mvnparse
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testGuavaPom = `<project>
+  <modelVersion>4.0.0</modelVersion>
+  <groupId>com.google.guava</groupId>
+  <artifactId>guava</artifactId>
+  <version>31.1-jre</version>
+  <name>Guava: Google Core Libraries for Java</name>
+  <url>https://github.com/google/guava</url>
+  <licenses>
+    <license>
+      <name>The Apache Software License, Version 2.0</name>
+      <url>http://www.apache.org/licenses/LICENSE-2.0.txt</url>
+    </license>
+  </licenses>
+</project>`
+
+func testRootProject() *Project {
+	return &Project{
+		GroupId:      "com.example",
+		ArtifactId:   "app",
+		Version:      "1.0",
+		Organization: &Organization{Name: "Example Corp", URL: "https://example.com"},
+		Developers:   &[]Developer{{Name: "Jane Doe", Email: "jane@example.com"}},
+		SCM:          &Scm{URL: "https://git.example.com/app"},
+	}
+}
+
+func TestCoordinates_PackageURL(t *testing.T) {
+	c := Coordinates{GroupId: "org.example", ArtifactId: "lib", Version: "1.0"}
+	assert.Equal(t, "pkg:maven/org.example/lib@1.0", c.PackageURL())
+	c.Type = "test-jar"
+	c.Classifier = "tests"
+	assert.Equal(t, "pkg:maven/org.example/lib@1.0?classifier=tests&type=test-jar", c.PackageURL())
+}
+
+func TestNewCycloneDX(t *testing.T) {
+	dir, repo := writeTestRepository(t)
+	defer os.RemoveAll(dir)
+	guava := Coordinates{GroupId: "com.google.guava", ArtifactId: "guava", Version: "31.1-jre"}
+	assert.NoError(t, ioutil.WriteFile(repo.PomPath(guava), []byte(testGuavaPom), 0644))
+
+	root := testGraph()
+	root.Project = testRootProject()
+	bom, err := NewCycloneDX(root, repo)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "Example Corp", bom.Metadata.Supplier.Name)
+	assert.Equal(t, "Jane Doe", bom.Metadata.Authors[0].Name)
+	assert.Equal(t, "pkg:maven/com.example/app@1.0", bom.Metadata.Component.BOMRef)
+	assert.Equal(t, CycloneDXExternalReferences{{Type: "vcs", URL: "https://git.example.com/app"}}, bom.Metadata.Component.ExternalReferences)
+	assert.Len(t, bom.Components, 6)
+
+	component := bom.Components[1]
+	assert.Equal(t, "pkg:maven/com.google.guava/guava@31.1-jre", component.Purl)
+	assert.Equal(t, "required", component.Scope)
+	assert.Equal(t, CycloneDXLicense{ID: "Apache-2.0", URL: "http://www.apache.org/licenses/LICENSE-2.0.txt"}, component.Licenses[0].License)
+	assert.Len(t, component.Hashes, 4)
+	assert.Equal(t, "SHA-1", component.Hashes[1].Alg)
+	assert.Equal(t, "optional", bom.Components[4].Scope)
+
+	assert.Equal(t, CycloneDXDependency{
+		Ref:       "pkg:maven/com.example/app@1.0",
+		DependsOn: []string{"pkg:maven/com.example/lib-a@1.0", "pkg:maven/com.example/lib-b@2.0", "pkg:maven/junit/junit@4.13.2"},
+	}, bom.Dependencies[0])
+
+	data, err := bom.ToJSON()
+	assert.NoError(t, err)
+	var doc map[string]interface{}
+	assert.NoError(t, json.Unmarshal(data, &doc))
+	assert.Equal(t, "CycloneDX", doc["bomFormat"])
+	assert.Equal(t, "1.5", doc["specVersion"])
+
+	xmlStr, err := bom.ToXMLStr()
+	assert.NoError(t, err)
+	assert.Contains(t, xmlStr, `<bom xmlns="http://cyclonedx.org/schema/bom/1.5" serialNumber="urn:uuid:`)
+	assert.Contains(t, xmlStr, `<dependency ref="pkg:maven/junit/junit@4.13.2">`)
+	assert.Contains(t, xmlStr, "<licenses>\n        <license>\n          <id>Apache-2.0</id>")
+	var parsed struct {
+		Components []struct {
+			Ref string `xml:"bom-ref,attr"`
+		} `xml:"components>component"`
+	}
+	assert.NoError(t, xml.Unmarshal([]byte(xmlStr), &parsed))
+	assert.Len(t, parsed.Components, 6)
+	assert.NotContains(t, xmlStr, "<hashes></hashes>")
+}
+
+func TestCycloneDXComponent_Licenses(t *testing.T) {
+	n := &DependencyNode{Dependency: Dependency{GroupId: "com.example", ArtifactId: "lib", Version: "1.0"}}
+	licenses := NormalizeLicenses([]*Project{{Licenses: &[]License{
+		{URL: "https://example.com/apache-2/LICENSE"},
+		{URL: "https://example.com/LICENSE"},
+		{},
+	}}})
+	// every license has an id or a name
+	assert.Equal(t, CycloneDXLicenses{
+		{License: CycloneDXLicense{Name: "Apache-2.0", URL: "https://example.com/apache-2/LICENSE"}},
+		{License: CycloneDXLicense{Name: "https://example.com/LICENSE", URL: "https://example.com/LICENSE"}},
+	}, cycloneDXComponent(n, nil, licenses, "library").Licenses)
+}
@@ -0,0 +1,552 @@
+package mvnparse
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/subchen/go-xmldom"
+)
+
+type ChangeKind string
+
+const (
+	ChangeAdded    ChangeKind = "added"
+	ChangeRemoved  ChangeKind = "removed"
+	ChangeModified ChangeKind = "changed"
+)
+
+// Change is a difference between two POMs. Section names what changed
+// (dependency, plugin, property...), Key identifies it within the section,
+// such as the management key of a dependency, and Field is the changed part
+// of a modified entry, such as version or configuration/source.
+type Change struct {
+	Kind ChangeKind `json:"kind"`
+	// Profile is the id of the profile the change is in, if any.
+	Profile string `json:"profile,omitempty"`
+	Section string `json:"section"`
+	Key     string `json:"key"`
+	Field   string `json:"field,omitempty"`
+	From    string `json:"from,omitempty"`
+	To      string `json:"to,omitempty"`
+}
+
+type ProjectDiff struct {
+	Changes []Change `json:"changes"`
+}
+
+// diffEntry is an entry of a POM section: summary describes it when added
+// or removed and fields are compared when it is in both POMs.
+type diffEntry struct {
+	key     string
+	summary string
+	fields  [][2]string
+}
+
+func (e *diffEntry) field(name, value string) {
+	if value != "" {
+		e.fields = append(e.fields, [2]string{name, value})
+	}
+}
+
+func (e *diffEntry) get(name string) (string, bool) {
+	for _, f := range e.fields {
+		if f[0] == name {
+			return f[1], true
+		}
+	}
+	return "", false
+}
+
+type differ struct {
+	profile string
+	changes []Change
+}
+
+// Diff lists the changes from a to b: coordinates, dependencies and managed
+// dependencies by management key, plugins and their executions,
+// extensions, properties, repositories and profiles, down to the elements
+// of plugin configurations. The coordinates of dependencies, plugins and
+// extensions are compared once interpolated with the properties of their
+// POM, or profile, so that a version bumped through a property shows on the
+// dependency as well as on the property.
+func Diff(a, b *Project) *ProjectDiff {
+	d := &differ{}
+	d.project(a, b)
+	d.sections(projectSections(a), projectSections(b))
+
+	var profilesA, profilesB []diffEntry
+	for _, p := range profileList(a) {
+		profilesA = append(profilesA, profileEntry(p))
+	}
+	for _, p := range profileList(b) {
+		profilesB = append(profilesB, profileEntry(p))
+	}
+	d.entries("profile", profilesA, profilesB)
+	for _, pa := range profileList(a) {
+		for _, pb := range profileList(b) {
+			if pa.Id == pb.Id {
+				sub := &differ{profile: pa.Id}
+				sub.sections(profileSections(a, &pa), profileSections(b, &pb))
+				d.changes = append(d.changes, sub.changes...)
+			}
+		}
+	}
+	return &ProjectDiff{Changes: d.changes}
+}
+
+func (d *differ) add(c Change) {
+	c.Profile = d.profile
+	d.changes = append(d.changes, c)
+}
+
+// entries compares the entries of a section, in the order of a, then the
+// ones only in b.
+func (d *differ) entries(section string, a, b []diffEntry) {
+	find := func(entries []diffEntry, key string) *diffEntry {
+		for i := range entries {
+			if entries[i].key == key {
+				return &entries[i]
+			}
+		}
+		return nil
+	}
+	for i := range a {
+		ea := &a[i]
+		eb := find(b, ea.key)
+		if eb == nil {
+			d.add(Change{Kind: ChangeRemoved, Section: section, Key: ea.key, From: ea.summary})
+			continue
+		}
+		var names []string
+		for _, f := range append(append([][2]string{}, ea.fields...), eb.fields...) {
+			if !containsString(names, f[0]) {
+				names = append(names, f[0])
+			}
+		}
+		for _, name := range names {
+			from, _ := ea.get(name)
+			to, _ := eb.get(name)
+			if from != to {
+				d.add(Change{Kind: ChangeModified, Section: section, Key: ea.key, Field: name, From: from, To: to})
+			}
+		}
+	}
+	for _, eb := range b {
+		if find(a, eb.key) == nil {
+			d.add(Change{Kind: ChangeAdded, Section: section, Key: eb.key, To: eb.summary})
+		}
+	}
+}
+
+type diffSection struct {
+	name    string
+	entries []diffEntry
+}
+
+func (d *differ) sections(a, b []diffSection) {
+	for i := range a {
+		d.entries(a[i].name, a[i].entries, b[i].entries)
+	}
+}
+
+func (d *differ) project(a, b *Project) {
+	entry := func(p *Project) diffEntry {
+		e := diffEntry{key: "project"}
+		e.field("groupId", p.GroupId)
+		e.field("artifactId", p.ArtifactId)
+		e.field("version", p.Interpolate(p.Version))
+		e.field("packaging", p.Packaging)
+		if p.Parent != nil {
+			e.field("parent", p.Interpolate(p.Parent.Coordinates().String()))
+		}
+		if p.Modules != nil {
+			e.field("modules", strings.Join(*p.Modules, ", "))
+		}
+		return e
+	}
+	d.entries("project", []diffEntry{entry(a)}, []diffEntry{entry(b)})
+}
+
+// projectSections returns the sections of p in a fixed order, so the
+// sections of two projects line up.
+func projectSections(p *Project) []diffSection {
+	resolve := p.Interpolate
+	var plugins, managedPlugins []Plugin
+	var extensions []Extension
+	if p.Build != nil {
+		if p.Build.Plugins != nil {
+			plugins = *p.Build.Plugins
+		}
+		if p.Build.PluginManagement != nil {
+			managedPlugins = p.Build.PluginManagement.Plugins
+		}
+		if p.Build.Extensions != nil {
+			extensions = *p.Build.Extensions
+		}
+	}
+	var extensionEntries []diffEntry
+	for _, ext := range extensions {
+		c := ext.Coordinates()
+		version := resolve(ext.Version)
+		e := diffEntry{key: resolve(c.GroupId + ":" + c.ArtifactId), summary: version}
+		e.field("version", version)
+		extensionEntries = append(extensionEntries, e)
+	}
+	return []diffSection{
+		{"dependency", dependencyEntries(resolve, p.Dependencies)},
+		{"managed dependency", dependencyEntries(resolve, managedDependencyList(p.DependencyManagement))},
+		{"plugin", pluginEntries(resolve, plugins)},
+		{"plugin execution", executionEntries(resolve, plugins)},
+		{"managed plugin", pluginEntries(resolve, managedPlugins)},
+		{"managed plugin execution", executionEntries(resolve, managedPlugins)},
+		{"extension", extensionEntries},
+		{"property", propertyEntries(p.Properties)},
+		{"repository", repositoryEntries(p.Repositories, nil)},
+		{"plugin repository", repositoryEntries(nil, p.PluginRepositories)},
+	}
+}
+
+// profileSections is projectSections for the profile p of project, its
+// properties overriding the ones of project.
+func profileSections(project *Project, p *Profile) []diffSection {
+	resolve := func(s string) string {
+		return interpolate(s, func(name string) (string, bool) {
+			if v, ok := p.Properties.Get(name); ok {
+				return v, true
+			}
+			return project.Property(name)
+		}, 0)
+	}
+	var plugins, managedPlugins []Plugin
+	if p.Build != nil {
+		if p.Build.Plugins != nil {
+			plugins = *p.Build.Plugins
+		}
+		if p.Build.PluginManagement != nil {
+			managedPlugins = p.Build.PluginManagement.Plugins
+		}
+	}
+	return []diffSection{
+		{"dependency", dependencyEntries(resolve, p.Dependencies)},
+		{"managed dependency", dependencyEntries(resolve, managedDependencyList(p.DependencyManagement))},
+		{"plugin", pluginEntries(resolve, plugins)},
+		{"plugin execution", executionEntries(resolve, plugins)},
+		{"managed plugin", pluginEntries(resolve, managedPlugins)},
+		{"managed plugin execution", executionEntries(resolve, managedPlugins)},
+		{"extension", nil},
+		{"property", propertyEntries(p.Properties)},
+		{"repository", repositoryEntries(p.Repositories, nil)},
+		{"plugin repository", repositoryEntries(nil, p.PluginRepositories)},
+	}
+}
+
+func profileList(p *Project) []Profile {
+	if p.Profiles == nil {
+		return nil
+	}
+	return *p.Profiles
+}
+
+func profileEntry(p Profile) diffEntry {
+	e := diffEntry{key: p.Id}
+	if a := p.Activation; a != nil {
+		if a.ActiveByDefault {
+			e.field("activeByDefault", "true")
+		}
+		e.field("jdk", a.JDK)
+		if a.Property != nil {
+			e.field("property", strings.TrimSuffix(a.Property.Name+"="+a.Property.Value, "="))
+		}
+		if a.OS != nil {
+			e.field("os", strings.Trim(strings.Join([]string{a.OS.Name, a.OS.Family, a.OS.Arch, a.OS.Version}, " "), " "))
+		}
+		if a.File != nil {
+			e.field("file exists", a.File.Exists)
+			e.field("file missing", a.File.Missing)
+		}
+	}
+	if p.Modules != nil {
+		e.field("modules", strings.Join(*p.Modules, ", "))
+	}
+	return e
+}
+
+func managedDependencyList(m *DependencyManagement) *[]Dependency {
+	if m == nil {
+		return nil
+	}
+	return m.Dependencies
+}
+
+func dependencyEntries(resolve func(string) string, deps *[]Dependency) []diffEntry {
+	if deps == nil {
+		return nil
+	}
+	var entries []diffEntry
+	for _, d := range *deps {
+		d.GroupId = resolve(d.GroupId)
+		d.ArtifactId = resolve(d.ArtifactId)
+		d.Version = resolve(d.Version)
+		e := diffEntry{key: d.ManagementKey(), summary: d.Version}
+		e.field("version", d.Version)
+		e.field("scope", d.Scope)
+		e.field("optional", d.Optional)
+		e.field("systemPath", d.SystemPath)
+		if d.Exclusions != nil {
+			var exclusions []string
+			for _, ex := range *d.Exclusions {
+				exclusions = append(exclusions, ex.GroupId+":"+ex.ArtifactId)
+			}
+			e.field("exclusions", strings.Join(exclusions, ", "))
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+func pluginKey(p Plugin) string {
+	c := p.Coordinates()
+	return c.GroupId + ":" + c.ArtifactId
+}
+
+func pluginEntries(resolve func(string) string, plugins []Plugin) []diffEntry {
+	var entries []diffEntry
+	for _, p := range plugins {
+		version := resolve(p.Version)
+		e := diffEntry{key: resolve(pluginKey(p)), summary: version}
+		e.field("version", version)
+		e.field("extensions", p.Extensions)
+		e.field("inherited", p.Inherited)
+		if p.Dependencies != nil {
+			var deps []string
+			for _, d := range *p.Dependencies {
+				deps = append(deps, resolve(d.Coordinates().String()))
+			}
+			e.field("dependencies", strings.Join(deps, ", "))
+		}
+		configurationFields(&e, p.Configuration)
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+func executionEntries(resolve func(string) string, plugins []Plugin) []diffEntry {
+	var entries []diffEntry
+	for _, p := range plugins {
+		if p.Executions == nil {
+			continue
+		}
+		for _, ex := range *p.Executions {
+			id := ex.Id
+			if id == "" {
+				id = "default"
+			}
+			var goals string
+			if ex.Goals != nil {
+				goals = strings.Join(*ex.Goals, ", ")
+			}
+			e := diffEntry{key: resolve(pluginKey(p)) + "@" + id, summary: goals}
+			e.field("phase", ex.Phase)
+			e.field("goals", goals)
+			e.field("inherited", ex.Inherited)
+			configurationFields(&e, ex.Configuration)
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+func propertyEntries(props *Properties) []diffEntry {
+	if props == nil {
+		return nil
+	}
+	var entries []diffEntry
+	for el := props.Entries.Front(); el != nil; el = el.Next() {
+		value := fmt.Sprint(el.Value)
+		e := diffEntry{key: fmt.Sprint(el.Key), summary: value}
+		e.field("value", value)
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+func repositoryEntries(repos *[]Repository, pluginRepos *[]PluginRepository) []diffEntry {
+	var entries []diffEntry
+	add := func(id, url, layout string, releases, snapshots *RepositoryPolicy) {
+		e := diffEntry{key: id, summary: url}
+		e.field("url", url)
+		e.field("layout", layout)
+		e.field("releases", policySummary(releases))
+		e.field("snapshots", policySummary(snapshots))
+		entries = append(entries, e)
+	}
+	if repos != nil {
+		for _, r := range *repos {
+			add(r.Id, r.URL, r.Layout, r.Releases, r.Snapshots)
+		}
+	}
+	if pluginRepos != nil {
+		for _, r := range *pluginRepos {
+			add(r.Id, r.URL, r.Layout, r.Releases, r.Snapshots)
+		}
+	}
+	return entries
+}
+
+func policySummary(p *RepositoryPolicy) string {
+	if p == nil {
+		return ""
+	}
+	var parts []string
+	for _, kv := range [][2]string{{"enabled", p.Enabled}, {"updatePolicy", p.UpdatePolicy}, {"checksumPolicy", p.ChecksumPolicy}} {
+		if kv[1] != "" {
+			parts = append(parts, kv[0]+"="+kv[1])
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// configurationFields adds a field for every text and attribute of the
+// configuration, named by its path such as configuration/source. Repeated
+// elements are told apart by key, as in configuration/compilerArgs/arg[.='-Xlint']
+// or configuration/mappings/mapping[id='web'], so that adding one does not
+// rename the others, and by position, as in arg[2], when they have no
+// unique key.
+func configurationFields(e *diffEntry, c *Configuration) {
+	if c == nil {
+		return
+	}
+	flattenNodes(e, "configuration", c.Children)
+}
+
+func flattenNodes(e *diffEntry, path string, nodes []*xmldom.Node) {
+	groups := map[string][]*xmldom.Node{}
+	for _, n := range nodes {
+		groups[n.Name] = append(groups[n.Name], n)
+	}
+	keys := map[*xmldom.Node]string{}
+	for _, group := range groups {
+		if len(group) > 1 {
+			for i, key := range configurationKeys(group) {
+				keys[group[i]] = key
+			}
+		}
+	}
+	for _, n := range nodes {
+		p := path + "/" + n.Name
+		if key, ok := keys[n]; ok {
+			p += "[" + key + "]"
+		}
+		for _, attr := range n.Attributes {
+			e.field(p+"/@"+attr.Name, attr.Value)
+		}
+		if len(n.Children) == 0 {
+			e.fields = append(e.fields, [2]string{p, strings.TrimSpace(n.Text)})
+			continue
+		}
+		flattenNodes(e, p, n.Children)
+	}
+}
+
+// configurationKeys returns the keys of elements of the same name: the
+// first of their id, groupId:artifactId, name and text unique to each, else
+// their position.
+func configurationKeys(nodes []*xmldom.Node) []string {
+	candidates := []func(n *xmldom.Node) (string, string){
+		func(n *xmldom.Node) (string, string) {
+			return "id", configurationValue(n, "id")
+		},
+		func(n *xmldom.Node) (string, string) {
+			artifactId := configurationValue(n, "artifactId")
+			if groupId := configurationValue(n, "groupId"); groupId != "" && artifactId != "" {
+				return "groupId:artifactId", groupId + ":" + artifactId
+			}
+			return "artifactId", artifactId
+		},
+		func(n *xmldom.Node) (string, string) {
+			return "name", configurationValue(n, "name")
+		},
+		func(n *xmldom.Node) (string, string) {
+			if len(n.Children) > 0 {
+				return ".", ""
+			}
+			return ".", strings.TrimSpace(n.Text)
+		},
+	}
+	for _, candidate := range candidates {
+		keys := make([]string, 0, len(nodes))
+		seen := map[string]bool{}
+		for _, n := range nodes {
+			name, value := candidate(n)
+			if value == "" || seen[name+value] {
+				break
+			}
+			seen[name+value] = true
+			keys = append(keys, name+"='"+value+"'")
+		}
+		if len(keys) == len(nodes) {
+			return keys
+		}
+	}
+	keys := make([]string, len(nodes))
+	for i := range nodes {
+		keys[i] = strconv.Itoa(i + 1)
+	}
+	return keys
+}
+
+// configurationValue returns the attribute or the text child name of n.
+func configurationValue(n *xmldom.Node, name string) string {
+	for _, attr := range n.Attributes {
+		if attr.Name == name {
+			return strings.TrimSpace(attr.Value)
+		}
+	}
+	for _, c := range n.Children {
+		if c.Name == name && len(c.Children) == 0 {
+			return strings.TrimSpace(c.Text)
+		}
+	}
+	return ""
+}
+
+// Text renders one change per line, such as
+// ~ dependency com.fasterxml.jackson.core:jackson-databind:jar version: 2.15.2 → 2.17.0
+func (d *ProjectDiff) Text() string {
+	var buf bytes.Buffer
+	for _, c := range d.Changes {
+		if c.Profile != "" {
+			fmt.Fprintf(&buf, "[profile %s] ", c.Profile)
+		}
+		switch c.Kind {
+		case ChangeAdded:
+			fmt.Fprintf(&buf, "+ %s %s", c.Section, c.Key)
+			if c.To != "" {
+				fmt.Fprintf(&buf, " %s", c.To)
+			}
+		case ChangeRemoved:
+			fmt.Fprintf(&buf, "- %s %s", c.Section, c.Key)
+			if c.From != "" {
+				fmt.Fprintf(&buf, " %s", c.From)
+			}
+		default:
+			fmt.Fprintf(&buf, "~ %s %s %s: %s → %s", c.Section, c.Key, c.Field, noneIfEmpty(c.From), noneIfEmpty(c.To))
+		}
+		buf.WriteByte('\n')
+	}
+	return buf.String()
+}
+
+func noneIfEmpty(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return s
+}
+
+func (d *ProjectDiff) ToJSON() ([]byte, error) {
+	return json.MarshalIndent(d, "", "  ")
+}
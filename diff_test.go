@@ -0,0 +1,254 @@
+package mvnparse
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testDiffBefore = `<project>
+  <groupId>com.example</groupId>
+  <artifactId>app</artifactId>
+  <version>1.0</version>
+  <properties>
+    <java.version>11</java.version>
+    <legacy>true</legacy>
+  </properties>
+  <dependencies>
+    <dependency>
+      <groupId>com.fasterxml.jackson.core</groupId>
+      <artifactId>jackson-databind</artifactId>
+      <version>2.15.2</version>
+    </dependency>
+    <dependency>
+      <groupId>commons-lang</groupId>
+      <artifactId>commons-lang</artifactId>
+      <version>2.6</version>
+    </dependency>
+  </dependencies>
+  <build>
+    <plugins>
+      <plugin>
+        <artifactId>maven-compiler-plugin</artifactId>
+        <version>3.11.0</version>
+        <configuration>
+          <release>11</release>
+          <compilerArgs>
+            <arg>-Xlint</arg>
+            <arg>-Werror</arg>
+          </compilerArgs>
+        </configuration>
+        <executions>
+          <execution>
+            <id>default-testCompile</id>
+            <phase>test-compile</phase>
+          </execution>
+        </executions>
+      </plugin>
+    </plugins>
+  </build>
+  <profiles>
+    <profile>
+      <id>ci</id>
+      <dependencies>
+        <dependency>
+          <groupId>junit</groupId>
+          <artifactId>junit</artifactId>
+          <version>4.13.1</version>
+        </dependency>
+      </dependencies>
+    </profile>
+  </profiles>
+</project>`
+
+const testDiffAfter = `<project>
+  <groupId>com.example</groupId>
+  <artifactId>app</artifactId>
+  <version>1.1</version>
+  <properties>
+    <java.version>17</java.version>
+  </properties>
+  <dependencies>
+    <dependency>
+      <groupId>com.fasterxml.jackson.core</groupId>
+      <artifactId>jackson-databind</artifactId>
+      <version>2.17.0</version>
+      <scope>runtime</scope>
+    </dependency>
+    <dependency>
+      <groupId>org.apache.commons</groupId>
+      <artifactId>commons-lang3</artifactId>
+      <version>3.14.0</version>
+    </dependency>
+  </dependencies>
+  <repositories>
+    <repository>
+      <id>internal</id>
+      <url>https://repo.example.com/maven2</url>
+    </repository>
+  </repositories>
+  <build>
+    <plugins>
+      <plugin>
+        <artifactId>maven-compiler-plugin</artifactId>
+        <version>3.11.0</version>
+        <configuration>
+          <release>17</release>
+          <compilerArgs>
+            <arg>-Xlint</arg>
+            <arg>-parameters</arg>
+          </compilerArgs>
+        </configuration>
+      </plugin>
+    </plugins>
+  </build>
+  <profiles>
+    <profile>
+      <id>ci</id>
+      <activation>
+        <property>
+          <name>env.CI</name>
+        </property>
+      </activation>
+      <dependencies>
+        <dependency>
+          <groupId>junit</groupId>
+          <artifactId>junit</artifactId>
+          <version>4.13.2</version>
+        </dependency>
+      </dependencies>
+    </profile>
+    <profile>
+      <id>release</id>
+    </profile>
+  </profiles>
+</project>`
+
+func TestDiff(t *testing.T) {
+	a, err := ParseStr(testDiffBefore)
+	assert.NoError(t, err)
+	b, err := ParseStr(testDiffAfter)
+	assert.NoError(t, err)
+
+	diff := Diff(a, b)
+	assert.Equal(t, `~ project project version: 1.0 → 1.1
+~ dependency com.fasterxml.jackson.core:jackson-databind:jar version: 2.15.2 → 2.17.0
+~ dependency com.fasterxml.jackson.core:jackson-databind:jar scope: (none) → runtime
+- dependency commons-lang:commons-lang:jar 2.6
++ dependency org.apache.commons:commons-lang3:jar 3.14.0
+~ plugin org.apache.maven.plugins:maven-compiler-plugin configuration/release: 11 → 17
+~ plugin org.apache.maven.plugins:maven-compiler-plugin configuration/compilerArgs/arg[.='-Werror']: -Werror → (none)
+~ plugin org.apache.maven.plugins:maven-compiler-plugin configuration/compilerArgs/arg[.='-parameters']: (none) → -parameters
+- plugin execution org.apache.maven.plugins:maven-compiler-plugin@default-testCompile
+~ property java.version value: 11 → 17
+- property legacy true
++ repository internal https://repo.example.com/maven2
+~ profile ci property: (none) → env.CI
++ profile release
+[profile ci] ~ dependency junit:junit:jar version: 4.13.1 → 4.13.2
+`, diff.Text())
+
+	data, err := diff.ToJSON()
+	assert.NoError(t, err)
+	var decoded ProjectDiff
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, diff.Changes, decoded.Changes)
+	assert.Contains(t, string(data), `"kind": "changed"`)
+
+	assert.Empty(t, Diff(a, a).Changes)
+}
+
+func TestDiff_Interpolated(t *testing.T) {
+	pom := `<project>
+  <groupId>com.example</groupId>
+  <artifactId>app</artifactId>
+  <version>1.0</version>
+  <properties>
+    <jackson.version>%s</jackson.version>
+  </properties>
+  <dependencies>
+    <dependency>
+      <groupId>com.fasterxml.jackson.core</groupId>
+      <artifactId>jackson-databind</artifactId>
+      <version>${jackson.version}</version>
+    </dependency>
+  </dependencies>
+  <profiles>
+    <profile>
+      <id>legacy</id>
+      <properties>
+        <jackson.version>2.12.7</jackson.version>
+      </properties>
+      <dependencies>
+        <dependency>
+          <groupId>com.fasterxml.jackson.core</groupId>
+          <artifactId>jackson-core</artifactId>
+          <version>${jackson.version}</version>
+        </dependency>
+      </dependencies>
+    </profile>
+  </profiles>
+</project>`
+	a, err := ParseStr(fmt.Sprintf(pom, "2.15.2"))
+	assert.NoError(t, err)
+	b, err := ParseStr(fmt.Sprintf(pom, "2.17.0"))
+	assert.NoError(t, err)
+
+	// the profile overrides the property, its dependency is unchanged
+	assert.Equal(t, `~ dependency com.fasterxml.jackson.core:jackson-databind:jar version: 2.15.2 → 2.17.0
+~ property jackson.version value: 2.15.2 → 2.17.0
+`, Diff(a, b).Text())
+}
+
+func TestDiff_Plugins(t *testing.T) {
+	before := `<project>
+  <groupId>com.example</groupId>
+  <artifactId>app</artifactId>
+  <version>1.0</version>
+  <build>
+    <plugins>
+      <plugin>
+        <groupId>org.codehaus.mojo</groupId>
+        <artifactId>exec-maven-plugin</artifactId>
+        <configuration>
+          <mappings>
+            <mapping><id>web</id><port>80</port></mapping>
+            <mapping><id>api</id><port>8080</port></mapping>
+          </mappings>
+          <flags>
+            <flag>a</flag>
+            <flag>a</flag>
+          </flags>
+        </configuration>
+        <executions>
+          <execution>
+            <id>run</id>
+          </execution>
+        </executions>
+      </plugin>
+    </plugins>
+  </build>
+</project>`
+	after := strings.NewReplacer(
+		"<version>1.0</version>", "<version>1.0</version>\n  <properties>\n    <plugin.group>org.codehaus.mojo</plugin.group>\n  </properties>",
+		"<groupId>org.codehaus.mojo</groupId>", "<groupId>${plugin.group}</groupId>",
+		"<mappings>", "<mappings>\n            <mapping><id>admin</id><port>9090</port></mapping>",
+		"</flags>", "  <flag>a</flag>\n          </flags>",
+	).Replace(before)
+	a, err := ParseStr(before)
+	assert.NoError(t, err)
+	b, err := ParseStr(after)
+	assert.NoError(t, err)
+
+	// the groupId moved to a property is the same plugin, the inserted
+	// mapping does not rename the others and the flags, without a unique
+	// key, are told apart by position
+	assert.Equal(t, `~ plugin org.codehaus.mojo:exec-maven-plugin configuration/mappings/mapping[id='admin']/id: (none) → admin
+~ plugin org.codehaus.mojo:exec-maven-plugin configuration/mappings/mapping[id='admin']/port: (none) → 9090
+~ plugin org.codehaus.mojo:exec-maven-plugin configuration/flags/flag[3]: (none) → a
++ property plugin.group org.codehaus.mojo
+`, Diff(a, b).Text())
+}
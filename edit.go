@@ -0,0 +1,178 @@
+package mvnparse
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrDependencyExists   = errors.New("dependency already declared")
+	ErrDependencyNotFound = errors.New("dependency not found")
+)
+
+// ManagementKey identifies d in a dependencies section the way Maven does,
+// as groupId:artifactId:type[:classifier], the type defaulting to jar.
+func (d Dependency) ManagementKey() string {
+	key := d.GroupId + ":" + d.ArtifactId + ":" + d.Coordinates().TypeOrDefault()
+	if d.Classifier != "" {
+		key += ":" + d.Classifier
+	}
+	return key
+}
+
+// normalizeManagementKey completes a key given as groupId:artifactId with the
+// default type.
+func normalizeManagementKey(key string) string {
+	if strings.Count(key, ":") == 1 {
+		return key + ":jar"
+	}
+	return key
+}
+
+func findDependency(deps *[]Dependency, key string) int {
+	if deps == nil {
+		return -1
+	}
+	key = normalizeManagementKey(key)
+	for i, d := range *deps {
+		if d.ManagementKey() == key {
+			return i
+		}
+	}
+	return -1
+}
+
+func addDependency(deps **[]Dependency, d Dependency) error {
+	if findDependency(*deps, d.ManagementKey()) >= 0 {
+		return fmt.Errorf("%s: %w", d.ManagementKey(), ErrDependencyExists)
+	}
+	if *deps == nil {
+		*deps = &[]Dependency{}
+	}
+	**deps = append(**deps, d)
+	return nil
+}
+
+func removeDependency(deps **[]Dependency, key string) error {
+	i := findDependency(*deps, key)
+	if i < 0 {
+		return fmt.Errorf("%s: %w", key, ErrDependencyNotFound)
+	}
+	remaining := append((**deps)[:i:i], (**deps)[i+1:]...)
+	if len(remaining) == 0 {
+		*deps = nil
+	} else {
+		*deps = &remaining
+	}
+	return nil
+}
+
+func dependencyAt(deps *[]Dependency, key string) (*Dependency, error) {
+	i := findDependency(deps, key)
+	if i < 0 {
+		return nil, fmt.Errorf("%s: %w", key, ErrDependencyNotFound)
+	}
+	return &(*deps)[i], nil
+}
+
+func (p *Project) managedDependencies() **[]Dependency {
+	if p.DependencyManagement == nil {
+		p.DependencyManagement = &DependencyManagement{}
+	}
+	return &p.DependencyManagement.Dependencies
+}
+
+// Dependency returns the dependency declared with the management key, or
+// groupId:artifactId for a jar, so it can be edited in place.
+func (p *Project) Dependency(key string) (*Dependency, error) {
+	return dependencyAt(p.Dependencies, key)
+}
+
+// AddDependency appends d to the dependencies, failing with
+// ErrDependencyExists when its management key is already declared.
+func (p *Project) AddDependency(d Dependency) error {
+	return addDependency(&p.Dependencies, d)
+}
+
+// RemoveDependency removes the dependency declared with the management key.
+func (p *Project) RemoveDependency(key string) error {
+	return removeDependency(&p.Dependencies, key)
+}
+
+// SetDependencyVersion sets the version of the dependency declared with the
+// management key. An empty version leaves it to dependency management.
+func (p *Project) SetDependencyVersion(key, version string) error {
+	d, err := p.Dependency(key)
+	if err != nil {
+		return err
+	}
+	d.Version = version
+	return nil
+}
+
+// AddExclusion excludes groupId:artifactId from the transitive dependencies
+// of the dependency declared with the management key. Existing exclusions
+// are kept as is.
+func (p *Project) AddExclusion(key, groupId, artifactId string) error {
+	d, err := p.Dependency(key)
+	if err != nil {
+		return err
+	}
+	d.AddExclusion(groupId, artifactId)
+	return nil
+}
+
+// AddExclusion excludes groupId:artifactId, unless already excluded.
+func (d *Dependency) AddExclusion(groupId, artifactId string) {
+	if d.Exclusions == nil {
+		d.Exclusions = &[]Exclusion{}
+	}
+	for _, e := range *d.Exclusions {
+		if e.GroupId == groupId && e.ArtifactId == artifactId {
+			return
+		}
+	}
+	*d.Exclusions = append(*d.Exclusions, Exclusion{GroupId: groupId, ArtifactId: artifactId})
+}
+
+// ManagedDependency returns the dependency management entry with the
+// management key.
+func (p *Project) ManagedDependency(key string) (*Dependency, error) {
+	if p.DependencyManagement == nil {
+		return nil, fmt.Errorf("%s: %w", key, ErrDependencyNotFound)
+	}
+	return dependencyAt(p.DependencyManagement.Dependencies, key)
+}
+
+// AddManagedDependency appends d to dependency management, creating the
+// section when needed.
+func (p *Project) AddManagedDependency(d Dependency) error {
+	return addDependency(p.managedDependencies(), d)
+}
+
+// RemoveManagedDependency removes the dependency management entry with the
+// management key.
+func (p *Project) RemoveManagedDependency(key string) error {
+	if p.DependencyManagement == nil {
+		return fmt.Errorf("%s: %w", key, ErrDependencyNotFound)
+	}
+	if err := removeDependency(&p.DependencyManagement.Dependencies, key); err != nil {
+		return err
+	}
+	if p.DependencyManagement.Dependencies == nil {
+		p.DependencyManagement = nil
+	}
+	return nil
+}
+
+// SetManagedDependencyVersion sets the version of the dependency management
+// entry with the management key.
+func (p *Project) SetManagedDependencyVersion(key, version string) error {
+	d, err := p.ManagedDependency(key)
+	if err != nil {
+		return err
+	}
+	d.Version = version
+	return nil
+}
@@ -0,0 +1,57 @@
+package mvnparse
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDependency_ManagementKey(t *testing.T) {
+	assert.Equal(t, "junit:junit:jar", Dependency{GroupId: "junit", ArtifactId: "junit", Version: "4.13.2"}.ManagementKey())
+	assert.Equal(t, "com.example:core:test-jar:tests", Dependency{GroupId: "com.example", ArtifactId: "core", Type: "test-jar", Classifier: "tests"}.ManagementKey())
+}
+
+func TestProject_EditDependencies(t *testing.T) {
+	p := &Project{GroupId: "com.example", ArtifactId: "app", Version: "1.0"}
+	guava := Dependency{GroupId: "com.google.guava", ArtifactId: "guava", Version: "31.1-jre"}
+	assert.NoError(t, p.AddDependency(guava))
+	assert.NoError(t, p.AddDependency(Dependency{GroupId: "com.google.guava", ArtifactId: "guava", Classifier: "sources"}))
+	err := p.AddDependency(guava)
+	assert.True(t, errors.Is(err, ErrDependencyExists))
+	assert.Len(t, *p.Dependencies, 2)
+
+	assert.NoError(t, p.SetDependencyVersion("com.google.guava:guava", "32.1.3-jre"))
+	assert.NoError(t, p.AddExclusion("com.google.guava:guava:jar", "com.google.code.findbugs", "jsr305"))
+	assert.NoError(t, p.AddExclusion("com.google.guava:guava", "com.google.code.findbugs", "jsr305"))
+	d, err := p.Dependency("com.google.guava:guava")
+	assert.NoError(t, err)
+	assert.Equal(t, "32.1.3-jre", d.Version)
+	assert.Equal(t, []Exclusion{{GroupId: "com.google.code.findbugs", ArtifactId: "jsr305"}}, *d.Exclusions)
+
+	assert.NoError(t, p.RemoveDependency("com.google.guava:guava:jar:sources"))
+	assert.NoError(t, p.RemoveDependency("com.google.guava:guava"))
+	assert.Nil(t, p.Dependencies)
+	err = p.RemoveDependency("com.google.guava:guava")
+	assert.True(t, errors.Is(err, ErrDependencyNotFound))
+	assert.Error(t, p.SetDependencyVersion("junit:junit", "4.13.2"))
+	assert.Error(t, p.AddExclusion("junit:junit", "org.hamcrest", "hamcrest-core"))
+}
+
+func TestProject_EditManagedDependencies(t *testing.T) {
+	p, err := ParseStr(testCorePom)
+	assert.NoError(t, err)
+	_, err = p.ManagedDependency("junit:junit")
+	assert.True(t, errors.Is(err, ErrDependencyNotFound))
+	assert.Error(t, p.RemoveManagedDependency("junit:junit"))
+
+	assert.NoError(t, p.AddManagedDependency(Dependency{GroupId: "junit", ArtifactId: "junit", Version: "4.13.1"}))
+	assert.True(t, errors.Is(p.AddManagedDependency(Dependency{GroupId: "junit", ArtifactId: "junit"}), ErrDependencyExists))
+	assert.NoError(t, p.SetManagedDependencyVersion("junit:junit", "4.13.2"))
+	xmlStr, err := p.ToXMLStr()
+	assert.NoError(t, err)
+	assert.Contains(t, xmlStr, "<dependencyManagement>\n\t\t<dependencies>\n\t\t\t<dependency>\n\t\t\t\t<groupId>junit</groupId>\n\t\t\t\t<artifactId>junit</artifactId>\n\t\t\t\t<version>4.13.2</version>")
+
+	assert.NoError(t, p.RemoveManagedDependency("junit:junit"))
+	assert.Nil(t, p.DependencyManagement)
+}
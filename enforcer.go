@@ -0,0 +1,266 @@
+package mvnparse
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// EnforcerRule names a rule after its Maven Enforcer counterpart.
+type EnforcerRule string
+
+const (
+	EnforcerDependencyConvergence             EnforcerRule = "dependencyConvergence"
+	EnforcerRequireUpperBoundDeps             EnforcerRule = "requireUpperBoundDeps"
+	EnforcerBannedDependencies                EnforcerRule = "bannedDependencies"
+	EnforcerBanDuplicatePomDependencyVersions EnforcerRule = "banDuplicatePomDependencyVersions"
+)
+
+// EnforcerViolation is a rule broken by a resolved graph. Paths lead from
+// the root to each node involved.
+type EnforcerViolation struct {
+	Rule EnforcerRule
+	// Key is the management key of the offending artifact.
+	Key     string
+	Message string
+	Paths   [][]*DependencyNode
+}
+
+// EnforcerRules selects the rules to enforce, the way the enforcer plugin
+// is configured.
+type EnforcerRules struct {
+	DependencyConvergence bool                `json:"dependencyConvergence,omitempty"`
+	RequireUpperBoundDeps bool                `json:"requireUpperBoundDeps,omitempty"`
+	BannedDependencies    *BannedDependencies `json:"bannedDependencies,omitempty"`
+	// BanDuplicatePomDependencyVersions checks the POM of the root node.
+	BanDuplicatePomDependencyVersions bool `json:"banDuplicatePomDependencyVersions,omitempty"`
+}
+
+// BannedDependencies bans the artifacts matching Excludes unless they match
+// Includes. Patterns are groupId[:artifactId[:version[:type[:scope]]]] with
+// * wildcards, the version possibly a range such as [1.0,2.0).
+type BannedDependencies struct {
+	Excludes []string `json:"excludes,omitempty"`
+	Includes []string `json:"includes,omitempty"`
+	// DirectOnly only checks the direct dependencies of the root.
+	DirectOnly bool `json:"directOnly,omitempty"`
+}
+
+type EnforcerReport struct {
+	Violations []EnforcerViolation
+	// Problems are found in the POM rather than in the graph.
+	Problems []Problem
+}
+
+// Enforce applies the rules to the verbose graph rooted at n.
+func (r *EnforcerRules) Enforce(n *DependencyNode) (*EnforcerReport, error) {
+	report := &EnforcerReport{}
+	if r.DependencyConvergence {
+		report.Violations = append(report.Violations, CheckDependencyConvergence(n)...)
+	}
+	if r.RequireUpperBoundDeps {
+		report.Violations = append(report.Violations, CheckUpperBoundDeps(n)...)
+	}
+	if r.BannedDependencies != nil {
+		violations, err := r.BannedDependencies.Check(n)
+		if err != nil {
+			return nil, err
+		}
+		report.Violations = append(report.Violations, violations...)
+	}
+	if r.BanDuplicatePomDependencyVersions {
+		if n.Project == nil {
+			return nil, fmt.Errorf("%s: no POM for %s", EnforcerBanDuplicatePomDependencyVersions, n.Coordinates())
+		}
+		report.Problems = append(report.Problems, n.Project.DuplicateDependencies()...)
+	}
+	return report, nil
+}
+
+type artifactOccurrence struct {
+	node *DependencyNode
+	path []*DependencyNode
+}
+
+// artifactOccurrences returns every node of the graph rooted at n but the
+// root, whether it won mediation or not, grouped by management key in walk
+// order.
+func artifactOccurrences(n *DependencyNode) ([]string, map[string][]artifactOccurrence) {
+	var keys []string
+	occurrences := map[string][]artifactOccurrence{}
+	n.Walk(func(node *DependencyNode, path []*DependencyNode) bool {
+		if len(path) == 1 {
+			return true
+		}
+		key := node.Dependency.ManagementKey()
+		if _, ok := occurrences[key]; !ok {
+			keys = append(keys, key)
+		}
+		occurrences[key] = append(occurrences[key], artifactOccurrence{node, path})
+		return true
+	})
+	return keys, occurrences
+}
+
+// CheckDependencyConvergence reports the artifacts found at different
+// versions in the verbose graph rooted at n, with the path to every
+// occurrence.
+func CheckDependencyConvergence(n *DependencyNode) []EnforcerViolation {
+	var violations []EnforcerViolation
+	keys, occurrences := artifactOccurrences(n)
+	for _, key := range keys {
+		var versions []string
+		var paths [][]*DependencyNode
+		for _, o := range occurrences[key] {
+			if !containsString(versions, o.node.Dependency.Version) {
+				versions = append(versions, o.node.Dependency.Version)
+			}
+			paths = append(paths, o.path)
+		}
+		if len(versions) > 1 {
+			violations = append(violations, EnforcerViolation{
+				Rule:    EnforcerDependencyConvergence,
+				Key:     key,
+				Message: fmt.Sprintf("%s does not converge: versions %s", key, strings.Join(versions, ", ")),
+				Paths:   paths,
+			})
+		}
+	}
+	return violations
+}
+
+// CheckUpperBoundDeps reports the artifacts resolved to a version lower
+// than one requested elsewhere in the verbose graph rooted at n. Requests
+// are the versions before dependency management; ranges are ignored. The
+// first path leads to the resolved node, the others to the higher requests.
+func CheckUpperBoundDeps(n *DependencyNode) []EnforcerViolation {
+	var violations []EnforcerViolation
+	keys, occurrences := artifactOccurrences(n)
+	for _, key := range keys {
+		var resolved *artifactOccurrence
+		for i, o := range occurrences[key] {
+			if o.node.Included() {
+				resolved = &occurrences[key][i]
+				break
+			}
+		}
+		if resolved == nil {
+			continue
+		}
+		highest := ""
+		paths := [][]*DependencyNode{resolved.path}
+		for _, o := range occurrences[key] {
+			requested := o.node.PremanagedVersion
+			if requested == "" {
+				requested = o.node.Dependency.Version
+			}
+			if isVersionRange(requested) || CompareVersions(requested, resolved.node.Dependency.Version) <= 0 {
+				continue
+			}
+			if highest == "" || CompareVersions(requested, highest) > 0 {
+				highest = requested
+			}
+			if o.node != resolved.node {
+				paths = append(paths, o.path)
+			}
+		}
+		if highest != "" {
+			violations = append(violations, EnforcerViolation{
+				Rule:    EnforcerRequireUpperBoundDeps,
+				Key:     key,
+				Message: fmt.Sprintf("%s resolves to %s but %s is requested", key, resolved.node.Dependency.Version, highest),
+				Paths:   paths,
+			})
+		}
+	}
+	return violations
+}
+
+// Check reports every included node of the graph rooted at n that is
+// banned, with the path that pulled it in.
+func (b *BannedDependencies) Check(n *DependencyNode) ([]EnforcerViolation, error) {
+	for _, pattern := range append(append([]string{}, b.Excludes...), b.Includes...) {
+		if err := checkArtifactPattern(pattern); err != nil {
+			return nil, err
+		}
+	}
+	var violations []EnforcerViolation
+	n.Walk(func(node *DependencyNode, path []*DependencyNode) bool {
+		if !node.Included() {
+			return false
+		}
+		if len(path) == 1 {
+			return true
+		}
+		for _, pattern := range b.Excludes {
+			if matchArtifact(pattern, node.Dependency) && !matchAnyArtifact(b.Includes, node.Dependency) {
+				violations = append(violations, EnforcerViolation{
+					Rule:    EnforcerBannedDependencies,
+					Key:     node.Dependency.ManagementKey(),
+					Message: fmt.Sprintf("%s is banned by %s", node.ArtifactString(false), pattern),
+					Paths:   [][]*DependencyNode{path},
+				})
+				break
+			}
+		}
+		return !b.DirectOnly
+	})
+	return violations, nil
+}
+
+// DuplicateDependencies reports the dependencies declared more than once in
+// the same section of p, whatever their versions, like the enforcer's
+// banDuplicatePomDependencyVersions. Paths point at the last declaration.
+func (p *Project) DuplicateDependencies() []Problem {
+	var problems []Problem
+	check := func(path, field string, deps *[]Dependency) {
+		if deps == nil {
+			return
+		}
+		var keys []string
+		counts := map[string]int{}
+		last := map[string]int{}
+		for i, d := range *deps {
+			key := d.ManagementKey()
+			if counts[key] == 0 {
+				keys = append(keys, key)
+			}
+			counts[key]++
+			last[key] = i
+		}
+		for _, key := range keys {
+			if counts[key] > 1 {
+				problems = append(problems, Problem{
+					Severity: SeverityError,
+					Message:  fmt.Sprintf("'%s.(groupId:artifactId:type:classifier)' must be unique: %s defined %d times.", field, key, counts[key]),
+					Path:     fmt.Sprintf("%s[%d]", path, last[key]+1),
+				})
+			}
+		}
+	}
+	check("/project/dependencies/dependency", "dependencies.dependency", p.Dependencies)
+	check("/project/dependencyManagement/dependencies/dependency", "dependencyManagement.dependencies.dependency", managedDependencyList(p.DependencyManagement))
+	if p.Profiles != nil {
+		for i, profile := range *p.Profiles {
+			path := fmt.Sprintf("/project/profiles/profile[%d]", i+1)
+			check(path+"/dependencies/dependency", fmt.Sprintf("profiles.profile[%s].dependencies.dependency", profile.Id), profile.Dependencies)
+			check(path+"/dependencyManagement/dependencies/dependency", fmt.Sprintf("profiles.profile[%s].dependencyManagement.dependencies.dependency", profile.Id), managedDependencyList(profile.DependencyManagement))
+		}
+	}
+	return problems
+}
+
+// Text lists the violations, each followed by its paths, then the problems.
+func (r *EnforcerReport) Text() string {
+	var buf bytes.Buffer
+	for _, v := range r.Violations {
+		fmt.Fprintf(&buf, "[%s] %s\n", v.Rule, v.Message)
+		for _, path := range v.Paths {
+			fmt.Fprintf(&buf, "    %s\n", FormatPath(path))
+		}
+	}
+	for _, p := range r.Problems {
+		fmt.Fprintf(&buf, "[%s] %s\n", EnforcerBanDuplicatePomDependencyVersions, p)
+	}
+	return buf.String()
+}
@@ -0,0 +1,116 @@
+package mvnparse
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckDependencyConvergence(t *testing.T) {
+	violations := CheckDependencyConvergence(testGraph())
+	assert.Len(t, violations, 1)
+	v := violations[0]
+	assert.Equal(t, EnforcerDependencyConvergence, v.Rule)
+	assert.Equal(t, "com.google.guava:guava:jar", v.Key)
+	assert.Equal(t, "com.google.guava:guava:jar does not converge: versions 31.1-jre, 30.0-jre", v.Message)
+	assert.Len(t, v.Paths, 2)
+	assert.Equal(t, "com.example:app:1.0 -> com.example:lib-b:2.0 -> com.google.guava:guava:30.0-jre", FormatPath(v.Paths[1]))
+}
+
+func TestCheckUpperBoundDeps(t *testing.T) {
+	assert.Empty(t, CheckUpperBoundDeps(testGraph()))
+
+	resolved := testNode("org.slf4j:slf4j-api:1.7.36", ScopeCompile)
+	requested := testNode("org.slf4j:slf4j-api:2.0.9", ScopeCompile)
+	requested.State = NodeOmittedForConflict
+	requested.Related = resolved
+	// dependency management downgraded the request of lib-a
+	managed := testNode("org.slf4j:slf4j-api:1.7.36", ScopeCompile)
+	managed.PremanagedVersion = "2.0.1"
+	managed.State = NodeOmittedForDuplicate
+	root := testNode("com.example:app:1.0", "",
+		resolved,
+		testNode("com.example:lib-a:1.0", ScopeCompile, managed),
+		testNode("com.example:lib-b:1.0", ScopeCompile, requested),
+	)
+
+	violations := CheckUpperBoundDeps(root)
+	assert.Len(t, violations, 1)
+	assert.Equal(t, "org.slf4j:slf4j-api:jar resolves to 1.7.36 but 2.0.9 is requested", violations[0].Message)
+	assert.Len(t, violations[0].Paths, 3)
+	assert.Equal(t, []*DependencyNode{root, resolved}, violations[0].Paths[0])
+}
+
+func TestBannedDependencies_Check(t *testing.T) {
+	b := &BannedDependencies{
+		Excludes: []string{"com.google.guava:guava:[,32)", "org.hamcrest", "*:slf4j-api"},
+		Includes: []string{"org.hamcrest:hamcrest-core:1.3"},
+	}
+	violations, err := b.Check(testGraph())
+	assert.NoError(t, err)
+	assert.Len(t, violations, 2)
+	assert.Equal(t, "com.google.guava:guava:jar:31.1-jre:compile is banned by com.google.guava:guava:[,32)", violations[0].Message)
+	assert.Equal(t, "com.example:app:1.0 -> com.example:lib-a:1.0 -> com.google.guava:guava:31.1-jre", FormatPath(violations[0].Paths[0]))
+	assert.Equal(t, "org.slf4j:slf4j-api:jar", violations[1].Key)
+
+	b.DirectOnly = true
+	violations, err = b.Check(testGraph())
+	assert.NoError(t, err)
+	assert.Empty(t, violations)
+
+	b.Excludes = []string{"junit:junit:[5,4)"}
+	_, err = b.Check(testGraph())
+	assert.Error(t, err)
+}
+
+func TestProject_DuplicateDependencies(t *testing.T) {
+	p, err := ParseStr(`<project>
+  <dependencies>
+    <dependency><groupId>junit</groupId><artifactId>junit</artifactId><version>4.13.1</version></dependency>
+    <dependency><groupId>junit</groupId><artifactId>junit</artifactId><version>4.13.2</version></dependency>
+    <dependency><groupId>junit</groupId><artifactId>junit</artifactId><classifier>tests</classifier></dependency>
+  </dependencies>
+  <profiles>
+    <profile>
+      <id>it</id>
+      <dependencies>
+        <dependency><groupId>a</groupId><artifactId>b</artifactId></dependency>
+        <dependency><groupId>a</groupId><artifactId>b</artifactId></dependency>
+        <dependency><groupId>a</groupId><artifactId>b</artifactId></dependency>
+      </dependencies>
+    </profile>
+  </profiles>
+</project>`)
+	assert.NoError(t, err)
+	assert.Equal(t, []Problem{
+		{Severity: SeverityError, Message: "'dependencies.dependency.(groupId:artifactId:type:classifier)' must be unique: junit:junit:jar defined 2 times.", Path: "/project/dependencies/dependency[2]"},
+		{Severity: SeverityError, Message: "'profiles.profile[it].dependencies.dependency.(groupId:artifactId:type:classifier)' must be unique: a:b:jar defined 3 times.", Path: "/project/profiles/profile[1]/dependencies/dependency[3]"},
+	}, p.DuplicateDependencies())
+}
+
+func TestEnforcerRules_Enforce(t *testing.T) {
+	root := testGraph()
+	root.Project = &Project{Dependencies: &[]Dependency{
+		{GroupId: "a", ArtifactId: "b"},
+		{GroupId: "a", ArtifactId: "b"},
+	}}
+	rules := &EnforcerRules{
+		DependencyConvergence:             true,
+		RequireUpperBoundDeps:             true,
+		BannedDependencies:                &BannedDependencies{Excludes: []string{"junit"}, DirectOnly: true},
+		BanDuplicatePomDependencyVersions: true,
+	}
+	report, err := rules.Enforce(root)
+	assert.NoError(t, err)
+	assert.Equal(t, `[dependencyConvergence] com.google.guava:guava:jar does not converge: versions 31.1-jre, 30.0-jre
+    com.example:app:1.0 -> com.example:lib-a:1.0 -> com.google.guava:guava:31.1-jre
+    com.example:app:1.0 -> com.example:lib-b:2.0 -> com.google.guava:guava:30.0-jre
+[bannedDependencies] junit:junit:jar:4.13.2:test is banned by junit
+    com.example:app:1.0 -> junit:junit:4.13.2
+[banDuplicatePomDependencyVersions] [ERROR] 'dependencies.dependency.(groupId:artifactId:type:classifier)' must be unique: a:b:jar defined 2 times. @ /project/dependencies/dependency[2]
+`, report.Text())
+
+	root.Project = nil
+	_, err = rules.Enforce(root)
+	assert.Error(t, err)
+}
@@ -0,0 +1,481 @@
+package mvnparse
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/subchen/go-xmldom"
+)
+
+// Provenance explains where the effective value of a field comes from.
+type Provenance struct {
+	Field string
+	Value string
+	// Steps lead from the declaration to the value: managing entries,
+	// imported BOMs and properties, in the order Maven applies them.
+	Steps []ProvenanceStep
+}
+
+type ProvenanceStep struct {
+	Location    Location
+	Profile     string
+	Description string
+}
+
+func (s ProvenanceStep) String() string {
+	desc := s.Description
+	if s.Profile != "" {
+		desc = fmt.Sprintf("%s (profile %s)", desc, s.Profile)
+	}
+	return fmt.Sprintf("%s: %s", s.Location, desc)
+}
+
+func (p *Provenance) Text() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s: %s\n", p.Field, p.Value)
+	for _, s := range p.Steps {
+		fmt.Fprintf(&buf, "  %s\n", s)
+	}
+	return buf.String()
+}
+
+type explainer struct {
+	repo     *LocalRepository
+	profiles []string
+	steps    []ProvenanceStep
+}
+
+// maxImportDepth bounds BOMs importing BOMs.
+const maxImportDepth = 8
+
+// ExplainDependencyVersion tells where the version of groupId:artifactId
+// used by pf comes from: its declaration in pf or a parent, the dependency
+// management entry or imported BOM providing a missing version, and the
+// properties involved, with the POMs from repo and the given active
+// profiles taken into account.
+func ExplainDependencyVersion(pf *ProjectFile, repo *LocalRepository, groupId, artifactId string, activeProfiles ...string) (*Provenance, error) {
+	return explainDependency(pf, repo, groupId, artifactId, "version", activeProfiles)
+}
+
+// ExplainDependencyScope is ExplainDependencyVersion for the scope.
+func ExplainDependencyScope(pf *ProjectFile, repo *LocalRepository, groupId, artifactId string, activeProfiles ...string) (*Provenance, error) {
+	return explainDependency(pf, repo, groupId, artifactId, "scope", activeProfiles)
+}
+
+// ExplainPluginVersion tells where the version of the build plugin
+// groupId:artifactId of pf comes from: its declaration in the build of pf,
+// a parent or an active profile, or the plugin management providing a
+// missing version, and the properties involved.
+func ExplainPluginVersion(pf *ProjectFile, repo *LocalRepository, groupId, artifactId string, activeProfiles ...string) (*Provenance, error) {
+	chain, err := ParentChain(pf, repo)
+	if err != nil {
+		return nil, err
+	}
+	e := &explainer{repo: repo, profiles: activeProfiles}
+	key := groupId + ":" + artifactId
+	prov := &Provenance{Field: key + " version"}
+	matches := func(p Plugin) bool {
+		return chain[0].Project.Interpolate(pluginKey(p)) == key
+	}
+
+	found, declared := e.findPlugin(chain, false, matches)
+	if declared && found.plugin.Version == "" {
+		e.add(found.file, found.profile, found.path, "declared without version")
+		found, declared = e.findPlugin(chain, false, func(p Plugin) bool {
+			return matches(p) && p.Version != ""
+		})
+	}
+	if declared {
+		e.add(found.file, found.profile, found.path+"/version", "declared with version %s", found.plugin.Version)
+	} else if found, declared = e.findPlugin(chain, true, func(p Plugin) bool { return matches(p) && p.Version != "" }); declared {
+		e.add(found.file, found.profile, found.path+"/version", "managed as %s", found.plugin.Version)
+	} else {
+		return nil, fmt.Errorf("no version for plugin %s in %s or its parents", key, pf.Path)
+	}
+	prov.Value = e.resolve(chain, found.plugin.Version)
+	prov.Steps = e.steps
+	return prov, nil
+}
+
+// ExplainPluginConfiguration tells where the element at path of the
+// configuration of the build plugin groupId:artifactId of pf, such as
+// release or compilerArgs/arg[2], comes from: the nearest declaration of
+// the plugin setting it, as Maven merges configurations child first, or
+// the plugin management otherwise.
+func ExplainPluginConfiguration(pf *ProjectFile, repo *LocalRepository, groupId, artifactId, path string, activeProfiles ...string) (*Provenance, error) {
+	chain, err := ParentChain(pf, repo)
+	if err != nil {
+		return nil, err
+	}
+	e := &explainer{repo: repo, profiles: activeProfiles}
+	key := groupId + ":" + artifactId
+	prov := &Provenance{Field: key + " configuration/" + path}
+	var node *xmldom.Node
+	configures := func(p Plugin) bool {
+		if chain[0].Project.Interpolate(pluginKey(p)) != key || p.Configuration == nil {
+			return false
+		}
+		node = configurationNode(p.Configuration.Children, path)
+		return node != nil
+	}
+
+	description := "configured as %s"
+	found, ok := e.findPlugin(chain, false, configures)
+	if !ok {
+		description = "managed as %s"
+		found, ok = e.findPlugin(chain, true, configures)
+	}
+	if !ok {
+		return nil, fmt.Errorf("plugin %s of %s and its parents does not configure %s", key, pf.Path, path)
+	}
+	e.add(found.file, found.profile, found.path+"/configuration/"+path, description, strings.TrimSpace(node.Text))
+	prov.Value = e.resolve(chain, strings.TrimSpace(node.Text))
+	prov.Steps = e.steps
+	return prov, nil
+}
+
+// configurationNode returns the element at path, such as compilerArgs/arg[2],
+// among nodes.
+func configurationNode(nodes []*xmldom.Node, path string) *xmldom.Node {
+	var node *xmldom.Node
+	for _, segment := range strings.Split(path, "/") {
+		name, index := segment, 1
+		if i := strings.Index(segment, "["); i >= 0 && strings.HasSuffix(segment, "]") {
+			n, err := strconv.Atoi(segment[i+1 : len(segment)-1])
+			if err != nil {
+				return nil
+			}
+			name, index = segment[:i], n
+		}
+		node = nil
+		for _, child := range nodes {
+			if child.Name == name {
+				if index--; index == 0 {
+					node = child
+					break
+				}
+			}
+		}
+		if node == nil {
+			return nil
+		}
+		nodes = node.Children
+	}
+	return node
+}
+
+// ExplainProperty tells which POM of the chain of pf, or active profile,
+// defines the property name and the properties its value refers to.
+func ExplainProperty(pf *ProjectFile, repo *LocalRepository, name string, activeProfiles ...string) (*Provenance, error) {
+	chain, err := ParentChain(pf, repo)
+	if err != nil {
+		return nil, err
+	}
+	e := &explainer{repo: repo, profiles: activeProfiles}
+	value := e.resolve(chain, "${"+name+"}")
+	if strings.Contains(value, "${") {
+		return nil, fmt.Errorf("property %s is not defined by %s or its parents", name, pf.Path)
+	}
+	return &Provenance{Field: "${" + name + "}", Value: value, Steps: e.steps}, nil
+}
+
+func explainDependency(pf *ProjectFile, repo *LocalRepository, groupId, artifactId, field string, profiles []string) (*Provenance, error) {
+	chain, err := ParentChain(pf, repo)
+	if err != nil {
+		return nil, err
+	}
+	e := &explainer{repo: repo, profiles: profiles}
+	prov := &Provenance{Field: fmt.Sprintf("%s:%s %s", groupId, artifactId, field)}
+	get := func(d Dependency) string {
+		if field == "scope" {
+			return d.Scope
+		}
+		return d.Version
+	}
+
+	key := groupId + ":" + artifactId + ":jar"
+	found, declared := e.find(chain, false, func(d Dependency) bool {
+		return pf.Project.Interpolate(d.GroupId) == groupId && pf.Project.Interpolate(d.ArtifactId) == artifactId
+	})
+	if declared {
+		key = found.dependency.ManagementKey()
+		if v := get(found.dependency); v != "" {
+			e.add(found.file, found.profile, found.path+"/"+field, "declared with %s %s", field, v)
+			prov.Value = e.resolve(chain, v)
+			prov.Steps = e.steps
+			return prov, nil
+		}
+		e.add(found.file, found.profile, found.path, "declared without %s", field)
+	}
+
+	if v, ok := e.managed(chain, key, get, 0); ok {
+		prov.Value = v
+	} else if field == "scope" && declared {
+		prov.Value = ScopeCompile
+		e.steps = append(e.steps, ProvenanceStep{Location: Location{File: pf.Path}, Description: "compile by default"})
+	} else {
+		return nil, fmt.Errorf("no %s for %s:%s in %s, its parents or imported BOMs", field, groupId, artifactId, pf.Path)
+	}
+	prov.Steps = e.steps
+	return prov, nil
+}
+
+type dependencyDeclaration struct {
+	dependency Dependency
+	file       *ProjectFile
+	profile    string
+	path       string
+}
+
+// find returns the first dependency matching in the dependencies, or the
+// dependency management when managed, of chain, nearest POM first and active
+// profiles before the POM they are in, as they override it.
+func (e *explainer) find(chain []*ProjectFile, managed bool, match func(Dependency) bool) (dependencyDeclaration, bool) {
+	for _, f := range chain {
+		for _, s := range e.dependencySections(f, managed) {
+			if s.deps == nil {
+				continue
+			}
+			for i, d := range *s.deps {
+				if match(d) {
+					return dependencyDeclaration{d, f, s.profile, fmt.Sprintf("%s[%d]", s.path, i+1)}, true
+				}
+			}
+		}
+	}
+	return dependencyDeclaration{}, false
+}
+
+type pluginDeclarationAt struct {
+	plugin  Plugin
+	file    *ProjectFile
+	profile string
+	path    string
+}
+
+// findPlugin returns the first plugin matching in the builds, or the
+// plugin management when managed, of chain, nearest POM first and active
+// profiles before the POM they are in. Plugins of parents not inherited are
+// skipped.
+func (e *explainer) findPlugin(chain []*ProjectFile, managed bool, match func(Plugin) bool) (pluginDeclarationAt, bool) {
+	for i, f := range chain {
+		for _, s := range e.buildSections(f) {
+			var plugins []Plugin
+			path := s.path + "/plugins/plugin"
+			if managed {
+				if s.build.PluginManagement != nil {
+					plugins = s.build.PluginManagement.Plugins
+				}
+				path = s.path + "/pluginManagement/plugins/plugin"
+			} else if s.build.Plugins != nil {
+				plugins = *s.build.Plugins
+			}
+			for j, p := range plugins {
+				if i > 0 && strings.TrimSpace(p.Inherited) == "false" {
+					continue
+				}
+				if match(p) {
+					return pluginDeclarationAt{p, f, s.profile, fmt.Sprintf("%s[%d]", path, j+1)}, true
+				}
+			}
+		}
+	}
+	return pluginDeclarationAt{}, false
+}
+
+type explainedBuild struct {
+	profile string
+	path    string
+	build   *BuildBase
+}
+
+func (e *explainer) buildSections(f *ProjectFile) []explainedBuild {
+	var sections []explainedBuild
+	if f.Project.Profiles != nil {
+		for i, p := range *f.Project.Profiles {
+			if e.active(f, p) && p.Build != nil {
+				sections = append(sections, explainedBuild{p.Id, fmt.Sprintf("/project/profiles/profile[%d]/build", i+1), p.Build})
+			}
+		}
+	}
+	if f.Project.Build != nil {
+		sections = append(sections, explainedBuild{"", "/project/build", &f.Project.Build.BuildBase})
+	}
+	return sections
+}
+
+// active reports whether the profile p of f is active: listed, or active
+// by default while no other profile of f is listed, as Maven does.
+func (e *explainer) active(f *ProjectFile, p Profile) bool {
+	if containsString(e.profiles, p.Id) {
+		return true
+	}
+	if p.Activation == nil || !p.Activation.ActiveByDefault {
+		return false
+	}
+	for _, other := range *f.Project.Profiles {
+		if containsString(e.profiles, other.Id) {
+			return false
+		}
+	}
+	return true
+}
+
+type dependencySection struct {
+	profile string
+	path    string
+	deps    *[]Dependency
+}
+
+func (e *explainer) dependencySections(f *ProjectFile, managed bool) []dependencySection {
+	var sections []dependencySection
+	if f.Project.Profiles != nil {
+		for i, p := range *f.Project.Profiles {
+			if !e.active(f, p) {
+				continue
+			}
+			path := fmt.Sprintf("/project/profiles/profile[%d]", i+1)
+			if managed {
+				sections = append(sections, dependencySection{p.Id, path + "/dependencyManagement/dependencies/dependency", managedDependencyList(p.DependencyManagement)})
+			} else {
+				sections = append(sections, dependencySection{p.Id, path + "/dependencies/dependency", p.Dependencies})
+			}
+		}
+	}
+	if managed {
+		sections = append(sections, dependencySection{"", "/project/dependencyManagement/dependencies/dependency", managedDependencyList(f.Project.DependencyManagement)})
+	} else {
+		sections = append(sections, dependencySection{"", "/project/dependencies/dependency", f.Project.Dependencies})
+	}
+	return sections
+}
+
+// managed looks for the management of key in chain, then in the BOMs it
+// imports, and resolves the value get returns.
+func (e *explainer) managed(chain []*ProjectFile, key string, get func(Dependency) string, depth int) (string, bool) {
+	entry, ok := e.find(chain, true, func(d Dependency) bool {
+		return d.Scope != ScopeImport && chain[0].Project.Interpolate(d.ManagementKey()) == key && get(d) != ""
+	})
+	if ok {
+		v := get(entry.dependency)
+		e.add(entry.file, entry.profile, entry.path, "managed as %s", v)
+		return e.resolve(chain, v), true
+	}
+	if depth >= maxImportDepth {
+		return "", false
+	}
+
+	for _, f := range chain {
+		for _, s := range e.dependencySections(f, true) {
+			if s.deps == nil {
+				continue
+			}
+			for i, d := range *s.deps {
+				if d.Scope != ScopeImport {
+					continue
+				}
+				// the steps of a BOM only matter if it manages key
+				saved := e.steps
+				bom := Coordinates{
+					GroupId:    chain[0].Project.Interpolate(d.GroupId),
+					ArtifactId: chain[0].Project.Interpolate(d.ArtifactId),
+					Version:    e.resolve(chain, d.Version),
+					Type:       "pom",
+				}
+				e.add(f, s.profile, fmt.Sprintf("%s[%d]", s.path, i+1), "imports BOM %s", bom)
+				bomChain, err := e.bomChain(bom)
+				if err == nil {
+					if v, ok := e.managed(bomChain, key, get, depth+1); ok {
+						return v, true
+					}
+				}
+				e.steps = saved
+			}
+		}
+	}
+	return "", false
+}
+
+func (e *explainer) bomChain(c Coordinates) ([]*ProjectFile, error) {
+	if e.repo == nil {
+		return nil, fmt.Errorf("no repository to read %s from", c)
+	}
+	bom, err := ParseFile(e.repo.PomPath(c))
+	if err != nil {
+		return nil, err
+	}
+	return ParentChain(bom, e.repo)
+}
+
+// resolve interpolates value with the properties of chain, recording
+// where each property is defined.
+func (e *explainer) resolve(chain []*ProjectFile, value string) string {
+	return interpolate(value, func(name string) (string, bool) {
+		for _, f := range chain {
+			for _, s := range e.propertySections(f) {
+				if v, ok := s.properties.Get(name); ok {
+					e.add(f, s.profile, s.path+"/"+name, "${%s} = %s", name, v)
+					return v, true
+				}
+			}
+		}
+		v, ok := chain[0].Project.Property(name)
+		if ok {
+			loc, inherited := builtinLocation(chain, name)
+			desc := fmt.Sprintf("${%s} = %s", name, v)
+			if inherited {
+				desc += ", inherited from the parent"
+			}
+			e.steps = append(e.steps, ProvenanceStep{Location: loc, Description: desc})
+		}
+		return v, ok
+	}, 0)
+}
+
+// builtinLocation returns where the element behind the built-in property
+// name, such as project.version, is declared, and whether it is the parent
+// section of a POM, the coordinate being inherited.
+func builtinLocation(chain []*ProjectFile, name string) (Location, bool) {
+	field := name[strings.Index(name, ".")+1:]
+	path := "/project/" + strings.Replace(field, ".", "/", -1)
+	for _, f := range chain {
+		if loc, ok := f.Locations.Lookup(path); ok {
+			return loc, false
+		}
+		if loc, ok := f.Locations.Lookup("/project/parent/" + field); ok {
+			return loc, true
+		}
+	}
+	return Location{File: chain[0].Path}, false
+}
+
+type propertySection struct {
+	profile    string
+	path       string
+	properties *Properties
+}
+
+func (e *explainer) propertySections(f *ProjectFile) []propertySection {
+	var sections []propertySection
+	if f.Project.Profiles != nil {
+		for i, p := range *f.Project.Profiles {
+			if e.active(f, p) && p.Properties != nil {
+				sections = append(sections, propertySection{p.Id, fmt.Sprintf("/project/profiles/profile[%d]/properties", i+1), p.Properties})
+			}
+		}
+	}
+	if f.Project.Properties != nil {
+		sections = append(sections, propertySection{"", "/project/properties", f.Project.Properties})
+	}
+	return sections
+}
+
+func (e *explainer) add(f *ProjectFile, profile, path, format string, args ...interface{}) {
+	loc, ok := f.Locations.Nearest(path)
+	if !ok {
+		loc = Location{File: f.Path}
+	}
+	e.steps = append(e.steps, ProvenanceStep{Location: loc, Profile: profile, Description: fmt.Sprintf(format, args...)})
+}
@@ -0,0 +1,284 @@
+package mvnparse
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testBomPom = `<project>
+  <modelVersion>4.0.0</modelVersion>
+  <groupId>com.google.guava</groupId>
+  <artifactId>guava-bom</artifactId>
+  <version>32.1.3-jre</version>
+  <packaging>pom</packaging>
+  <dependencyManagement>
+    <dependencies>
+      <dependency>
+        <groupId>com.google.guava</groupId>
+        <artifactId>guava</artifactId>
+        <version>${project.version}</version>
+      </dependency>
+    </dependencies>
+  </dependencyManagement>
+</project>`
+
+const testBomUserPom = `<project>
+  <modelVersion>4.0.0</modelVersion>
+  <groupId>com.example</groupId>
+  <artifactId>service</artifactId>
+  <version>1.0</version>
+  <properties>
+    <guava.version>32.1.3-jre</guava.version>
+  </properties>
+  <dependencyManagement>
+    <dependencies>
+      <dependency>
+        <groupId>com.google.guava</groupId>
+        <artifactId>guava-bom</artifactId>
+        <version>${guava.version}</version>
+        <type>pom</type>
+        <scope>import</scope>
+      </dependency>
+    </dependencies>
+  </dependencyManagement>
+  <dependencies>
+    <dependency>
+      <groupId>com.google.guava</groupId>
+      <artifactId>guava</artifactId>
+    </dependency>
+  </dependencies>
+  <profiles>
+    <profile>
+      <id>legacy</id>
+      <properties>
+        <guava.version>31.1-jre</guava.version>
+      </properties>
+    </profile>
+  </profiles>
+</project>`
+
+func TestExplainDependencyVersion(t *testing.T) {
+	dir := writeTestReactor(t)
+	defer os.RemoveAll(dir)
+	core, err := ParseFile(filepath.Join(dir, "core"))
+	assert.NoError(t, err)
+	root := filepath.Join(dir, "pom.xml")
+
+	p, err := ExplainDependencyVersion(core, nil, "com.fasterxml.jackson.core", "jackson-databind")
+	assert.NoError(t, err)
+	assert.Equal(t, "2.15.2", p.Value)
+	assert.Equal(t, []ProvenanceStep{
+		{Location: Location{core.Path, 10, 5}, Description: "declared without version"},
+		{Location: Location{root, 24, 7}, Description: "managed as ${jackson.version}"},
+		{Location: Location{root, 15, 5}, Description: "${jackson.version} = 2.15.2"},
+	}, p.Steps)
+
+	p, err = ExplainDependencyVersion(core, nil, "junit", "junit")
+	assert.NoError(t, err)
+	assert.Equal(t, "junit:junit version: 4.13.2\n  "+core.Path+":17:7: declared with version 4.13.2\n", p.Text())
+
+	_, err = ExplainDependencyVersion(core, nil, "org.slf4j", "slf4j-api")
+	assert.Error(t, err)
+}
+
+func TestExplainDependencyVersion_Bom(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{
+		"pom.xml": testBomUserPom,
+		"repository/com/google/guava/guava-bom/32.1.3-jre/guava-bom-32.1.3-jre.pom": testBomPom,
+	})
+	defer os.RemoveAll(dir)
+	repo := NewLocalRepository(filepath.Join(dir, "repository"))
+	pf, err := ParseFile(dir)
+	assert.NoError(t, err)
+	bom := repo.PomPath(Coordinates{GroupId: "com.google.guava", ArtifactId: "guava-bom", Version: "32.1.3-jre", Type: "pom"})
+
+	p, err := ExplainDependencyVersion(pf, repo, "com.google.guava", "guava")
+	assert.NoError(t, err)
+	assert.Equal(t, "32.1.3-jre", p.Value)
+	assert.Equal(t, []ProvenanceStep{
+		{Location: Location{pf.Path, 21, 5}, Description: "declared without version"},
+		{Location: Location{pf.Path, 7, 5}, Description: "${guava.version} = 32.1.3-jre"},
+		{Location: Location{pf.Path, 11, 7}, Description: "imports BOM com.google.guava:guava-bom:pom:32.1.3-jre"},
+		{Location: Location{bom, 9, 7}, Description: "managed as ${project.version}"},
+		{Location: Location{bom, 5, 3}, Description: "${project.version} = 32.1.3-jre"},
+	}, p.Steps)
+
+	// the BOM of the profile version is not in the repository
+	_, err = ExplainDependencyVersion(pf, repo, "com.google.guava", "guava", "legacy")
+	assert.Error(t, err)
+
+	p, err = ExplainProperty(pf, repo, "guava.version", "legacy")
+	assert.NoError(t, err)
+	assert.Equal(t, "31.1-jre", p.Value)
+	assert.Equal(t, []ProvenanceStep{
+		{Location: Location{pf.Path, 30, 9}, Profile: "legacy", Description: "${guava.version} = 31.1-jre"},
+	}, p.Steps)
+}
+
+func TestExplainDependencyScope(t *testing.T) {
+	dir := writeTestReactor(t)
+	defer os.RemoveAll(dir)
+	core, err := ParseFile(filepath.Join(dir, "core"))
+	assert.NoError(t, err)
+
+	p, err := ExplainDependencyScope(core, nil, "junit", "junit")
+	assert.NoError(t, err)
+	assert.Equal(t, "test", p.Value)
+
+	p, err = ExplainDependencyScope(core, nil, "com.fasterxml.jackson.core", "jackson-databind")
+	assert.NoError(t, err)
+	assert.Equal(t, ScopeCompile, p.Value)
+	assert.Equal(t, "compile by default", p.Steps[len(p.Steps)-1].Description)
+}
+
+func TestExplainProperty(t *testing.T) {
+	dir := writeTestReactor(t)
+	defer os.RemoveAll(dir)
+	app, err := ParseFile(filepath.Join(dir, "app"))
+	assert.NoError(t, err)
+
+	p, err := ExplainProperty(app, nil, "jackson.version")
+	assert.NoError(t, err)
+	assert.Equal(t, "${jackson.version}: 2.15.2\n  "+filepath.Join(dir, "pom.xml")+":15:5: ${jackson.version} = 2.15.2\n", p.Text())
+
+	// the version of app is inherited from its parent section
+	p, err = ExplainProperty(app, nil, "project.version")
+	assert.NoError(t, err)
+	assert.Equal(t, "1.0-SNAPSHOT", p.Value)
+	assert.Equal(t, ProvenanceStep{Location: Location{app.Path, 6, 5}, Description: "${project.version} = 1.0-SNAPSHOT, inherited from the parent"}, p.Steps[0])
+
+	_, err = ExplainProperty(app, nil, "missing")
+	assert.Error(t, err)
+}
+
+const testPluginParentPom = `<project>
+  <modelVersion>4.0.0</modelVersion>
+  <groupId>com.example</groupId>
+  <artifactId>parent</artifactId>
+  <version>1.0</version>
+  <packaging>pom</packaging>
+  <properties>
+    <compiler.version>3.11.0</compiler.version>
+  </properties>
+  <build>
+    <pluginManagement>
+      <plugins>
+        <plugin>
+          <artifactId>maven-compiler-plugin</artifactId>
+          <version>${compiler.version}</version>
+          <configuration>
+            <compilerArgs>
+              <arg>-Xlint</arg>
+              <arg>-parameters</arg>
+            </compilerArgs>
+          </configuration>
+        </plugin>
+      </plugins>
+    </pluginManagement>
+    <plugins>
+      <plugin>
+        <artifactId>maven-surefire-plugin</artifactId>
+        <version>3.2.2</version>
+      </plugin>
+    </plugins>
+  </build>
+  <profiles>
+    <profile>
+      <id>latest</id>
+      <activation>
+        <activeByDefault>true</activeByDefault>
+      </activation>
+      <properties>
+        <compiler.version>3.12.1</compiler.version>
+      </properties>
+    </profile>
+    <profile>
+      <id>legacy</id>
+    </profile>
+  </profiles>
+</project>`
+
+const testPluginChildPom = `<project>
+  <modelVersion>4.0.0</modelVersion>
+  <parent>
+    <groupId>com.example</groupId>
+    <artifactId>parent</artifactId>
+    <version>1.0</version>
+  </parent>
+  <artifactId>child</artifactId>
+  <build>
+    <plugins>
+      <plugin>
+        <artifactId>maven-compiler-plugin</artifactId>
+        <configuration>
+          <release>17</release>
+        </configuration>
+      </plugin>
+      <plugin>
+        <artifactId>maven-surefire-plugin</artifactId>
+      </plugin>
+    </plugins>
+  </build>
+</project>`
+
+func TestExplainPluginVersion(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{
+		"pom.xml":       testPluginParentPom,
+		"child/pom.xml": testPluginChildPom,
+	})
+	defer os.RemoveAll(dir)
+	child, err := ParseFile(filepath.Join(dir, "child"))
+	assert.NoError(t, err)
+	parent := filepath.Join(dir, "pom.xml")
+
+	// the profile active by default overrides the property
+	p, err := ExplainPluginVersion(child, nil, "org.apache.maven.plugins", "maven-compiler-plugin")
+	assert.NoError(t, err)
+	assert.Equal(t, "3.12.1", p.Value)
+	assert.Equal(t, []ProvenanceStep{
+		{Location: Location{child.Path, 11, 7}, Description: "declared without version"},
+		{Location: Location{parent, 15, 11}, Description: "managed as ${compiler.version}"},
+		{Location: Location{parent, 39, 9}, Profile: "latest", Description: "${compiler.version} = 3.12.1"},
+	}, p.Steps)
+
+	// unless another profile of the POM is active
+	p, err = ExplainPluginVersion(child, nil, "org.apache.maven.plugins", "maven-compiler-plugin", "legacy")
+	assert.NoError(t, err)
+	assert.Equal(t, "3.11.0", p.Value)
+
+	p, err = ExplainPluginVersion(child, nil, "org.apache.maven.plugins", "maven-surefire-plugin")
+	assert.NoError(t, err)
+	assert.Equal(t, "3.2.2", p.Value)
+	assert.Equal(t, "declared with version 3.2.2", p.Steps[1].Description)
+	assert.Equal(t, Location{parent, 28, 9}, p.Steps[1].Location)
+
+	_, err = ExplainPluginVersion(child, nil, "org.apache.maven.plugins", "maven-jar-plugin")
+	assert.Error(t, err)
+}
+
+func TestExplainPluginConfiguration(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{
+		"pom.xml":       testPluginParentPom,
+		"child/pom.xml": testPluginChildPom,
+	})
+	defer os.RemoveAll(dir)
+	child, err := ParseFile(filepath.Join(dir, "child"))
+	assert.NoError(t, err)
+
+	p, err := ExplainPluginConfiguration(child, nil, "org.apache.maven.plugins", "maven-compiler-plugin", "release")
+	assert.NoError(t, err)
+	assert.Equal(t, "org.apache.maven.plugins:maven-compiler-plugin configuration/release: 17\n  "+child.Path+":14:11: configured as 17\n", p.Text())
+
+	p, err = ExplainPluginConfiguration(child, nil, "org.apache.maven.plugins", "maven-compiler-plugin", "compilerArgs/arg[2]")
+	assert.NoError(t, err)
+	assert.Equal(t, "-parameters", p.Value)
+	assert.Equal(t, []ProvenanceStep{
+		{Location: Location{filepath.Join(dir, "pom.xml"), 19, 15}, Description: "managed as -parameters"},
+	}, p.Steps)
+
+	_, err = ExplainPluginConfiguration(child, nil, "org.apache.maven.plugins", "maven-compiler-plugin", "source")
+	assert.Error(t, err)
+}
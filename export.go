@@ -0,0 +1,255 @@
+package mvnparse
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	EdgeModule     = "module"
+	EdgeParent     = "parent"
+	EdgeDependency = "dependency"
+)
+
+// Graph is a plain node and edge list, the common form the module graph
+// and dependency graphs are exported from.
+type Graph struct {
+	Nodes []GraphNode
+	Edges []GraphEdge
+
+	// nodeIds and edgeSet index Nodes and Edges while the graph is built.
+	nodeIds map[string]bool
+	edgeSet map[GraphEdge]bool
+}
+
+type GraphNode struct {
+	Id      string
+	GroupId string
+	Label   string
+}
+
+type GraphEdge struct {
+	From     string
+	To       string
+	Kind     string
+	Scope    string
+	Optional bool
+}
+
+func (g *Graph) addNode(c Coordinates) string {
+	id := c.String()
+	if g.nodeIds == nil {
+		g.nodeIds = map[string]bool{}
+		for _, n := range g.Nodes {
+			g.nodeIds[n.Id] = true
+		}
+	}
+	if !g.nodeIds[id] {
+		g.nodeIds[id] = true
+		g.Nodes = append(g.Nodes, GraphNode{Id: id, GroupId: c.GroupId, Label: id})
+	}
+	return id
+}
+
+func (g *Graph) addEdge(e GraphEdge) {
+	if g.edgeSet == nil {
+		g.edgeSet = map[GraphEdge]bool{}
+		for _, existing := range g.Edges {
+			g.edgeSet[existing] = true
+		}
+	}
+	if !g.edgeSet[e] {
+		g.edgeSet[e] = true
+		g.Edges = append(g.Edges, e)
+	}
+}
+
+// ModuleGraph returns the reactor projects with module edges from
+// aggregators to their modules and parent edges from children to their
+// parent, when the parent belongs to the reactor.
+func (r *Reactor) ModuleGraph() *Graph {
+	g := &Graph{}
+	for _, pf := range r.Projects {
+		g.addNode(pf.Project.Coordinates())
+	}
+	for _, pf := range r.Projects {
+		from := pf.Project.Coordinates().String()
+		for _, module := range pf.Modules {
+			g.addEdge(GraphEdge{From: from, To: module.Project.Coordinates().String(), Kind: EdgeModule})
+		}
+		if parent := pf.Project.Parent; parent != nil {
+			if ppf := r.Find(parent.GroupId, parent.ArtifactId); ppf != nil {
+				g.addEdge(GraphEdge{From: from, To: ppf.Project.Coordinates().String(), Kind: EdgeParent})
+			}
+		}
+	}
+	return g
+}
+
+// Graph returns the included nodes of the dependency graph rooted at n.
+func (n *DependencyNode) Graph() *Graph {
+	g := &Graph{}
+	g.addNode(n.Coordinates())
+	n.Walk(func(node *DependencyNode, path []*DependencyNode) bool {
+		if !node.Included() {
+			return false
+		}
+		if len(path) > 1 {
+			parent := path[len(path)-2]
+			g.addEdge(GraphEdge{
+				From:     parent.Coordinates().String(),
+				To:       g.addNode(node.Coordinates()),
+				Kind:     EdgeDependency,
+				Scope:    node.Scope(),
+				Optional: node.Optional(),
+			})
+		}
+		return true
+	})
+	return g
+}
+
+// Filter returns the nodes whose groupId matches one of the patterns, * being
+// a wildcard, and the edges between them. Without patterns g is returned.
+func (g *Graph) Filter(groupIdPatterns ...string) *Graph {
+	if len(groupIdPatterns) == 0 {
+		return g
+	}
+	filtered := &Graph{}
+	kept := map[string]bool{}
+	for _, n := range g.Nodes {
+		if matchAnyWildcard(groupIdPatterns, n.GroupId) {
+			filtered.Nodes = append(filtered.Nodes, n)
+			kept[n.Id] = true
+		}
+	}
+	for _, e := range g.Edges {
+		if kept[e.From] && kept[e.To] {
+			filtered.Edges = append(filtered.Edges, e)
+		}
+	}
+	return filtered
+}
+
+func (e GraphEdge) label() string {
+	if e.Kind != EdgeDependency {
+		return e.Kind
+	}
+	label := e.Scope
+	if e.Optional {
+		label += " (optional)"
+	}
+	return label
+}
+
+func (g *Graph) WriteDOT(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "digraph {")
+	for _, n := range g.Nodes {
+		fmt.Fprintf(bw, "  %s [label=%s];\n", strconv.Quote(n.Id), strconv.Quote(n.Label))
+	}
+	for _, e := range g.Edges {
+		attrs := []string{"label=" + strconv.Quote(e.label()), "kind=" + strconv.Quote(e.Kind)}
+		if e.Scope != "" {
+			attrs = append(attrs, "scope="+strconv.Quote(e.Scope))
+		}
+		if e.Optional {
+			attrs = append(attrs, `optional="true"`, "style=dashed")
+		}
+		fmt.Fprintf(bw, "  %s -> %s [%s];\n", strconv.Quote(e.From), strconv.Quote(e.To), strings.Join(attrs, ", "))
+	}
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+func (g *Graph) WriteMermaid(w io.Writer) error {
+	ids := map[string]string{}
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "graph LR")
+	for i, n := range g.Nodes {
+		ids[n.Id] = "n" + strconv.Itoa(i)
+		fmt.Fprintf(bw, "  %s[\"%s\"]\n", ids[n.Id], strings.Replace(n.Label, `"`, "#quot;", -1))
+	}
+	for _, e := range g.Edges {
+		arrow := "-->"
+		if e.Optional {
+			arrow = "-.->"
+		}
+		fmt.Fprintf(bw, "  %s %s|%s| %s\n", ids[e.From], arrow, e.label(), ids[e.To])
+	}
+	return bw.Flush()
+}
+
+func (g *Graph) WriteGraphML(w io.Writer) error {
+	type data struct {
+		Key   string `xml:"key,attr"`
+		Value string `xml:",chardata"`
+	}
+	type key struct {
+		Id       string `xml:"id,attr"`
+		For      string `xml:"for,attr"`
+		AttrName string `xml:"attr.name,attr"`
+		AttrType string `xml:"attr.type,attr"`
+	}
+	type node struct {
+		Id   string `xml:"id,attr"`
+		Data []data `xml:"data"`
+	}
+	type edge struct {
+		Source string `xml:"source,attr"`
+		Target string `xml:"target,attr"`
+		Data   []data `xml:"data"`
+	}
+	type graphml struct {
+		XMLName xml.Name `xml:"http://graphml.graphdrawing.org/xmlns graphml"`
+		Keys    []key    `xml:"key"`
+		Graph   struct {
+			Id          string `xml:"id,attr"`
+			EdgeDefault string `xml:"edgedefault,attr"`
+			Nodes       []node `xml:"node"`
+			Edges       []edge `xml:"edge"`
+		} `xml:"graph"`
+	}
+
+	doc := graphml{Keys: []key{
+		{Id: "label", For: "node", AttrName: "label", AttrType: "string"},
+		{Id: "groupId", For: "node", AttrName: "groupId", AttrType: "string"},
+		{Id: "kind", For: "edge", AttrName: "kind", AttrType: "string"},
+		{Id: "scope", For: "edge", AttrName: "scope", AttrType: "string"},
+		{Id: "optional", For: "edge", AttrName: "optional", AttrType: "boolean"},
+	}}
+	doc.Graph.Id = "G"
+	doc.Graph.EdgeDefault = "directed"
+	for _, n := range g.Nodes {
+		doc.Graph.Nodes = append(doc.Graph.Nodes, node{Id: n.Id, Data: []data{
+			{Key: "label", Value: n.Label},
+			{Key: "groupId", Value: n.GroupId},
+		}})
+	}
+	for _, e := range g.Edges {
+		ed := edge{Source: e.From, Target: e.To, Data: []data{{Key: "kind", Value: e.Kind}}}
+		if e.Kind == EdgeDependency {
+			ed.Data = append(ed.Data,
+				data{Key: "scope", Value: e.Scope},
+				data{Key: "optional", Value: strconv.FormatBool(e.Optional)})
+		}
+		doc.Graph.Edges = append(doc.Graph.Edges, ed)
+	}
+
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	err = enc.Encode(doc)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,93 @@
+package mvnparse
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReactor_ModuleGraph(t *testing.T) {
+	dir := writeTestReactor(t)
+	defer os.RemoveAll(dir)
+	r, err := LoadReactor(dir)
+	assert.NoError(t, err)
+
+	g := r.ModuleGraph()
+	assert.Len(t, g.Nodes, 3)
+	assert.Contains(t, g.Edges, GraphEdge{From: "com.example:root:pom:1.0-SNAPSHOT", To: "com.example:core:1.0-SNAPSHOT", Kind: EdgeModule})
+	assert.Contains(t, g.Edges, GraphEdge{From: "com.example:app:1.0-SNAPSHOT", To: "com.example:root:pom:1.0-SNAPSHOT", Kind: EdgeParent})
+	assert.Len(t, g.Edges, 4)
+}
+
+func TestDependencyNode_Graph(t *testing.T) {
+	g := testGraph().Graph()
+	assert.Len(t, g.Nodes, 7)
+	assert.Contains(t, g.Edges, GraphEdge{From: "junit:junit:4.13.2", To: "org.hamcrest:hamcrest-core:1.3", Kind: EdgeDependency, Scope: ScopeTest})
+
+	filtered := g.Filter("com.example", "com.google.*")
+	assert.Len(t, filtered.Nodes, 4)
+	assert.Len(t, filtered.Edges, 3)
+	assert.Equal(t, g, g.Filter())
+}
+
+func TestDependencyNode_Graph_Duplicates(t *testing.T) {
+	// every library depends on the same two artifacts
+	root := testNode("com.example:app:1.0", "")
+	for i := 0; i < 2000; i++ {
+		root.Children = append(root.Children, testNode(fmt.Sprintf("com.example:lib-%d:1.0", i%500), ScopeCompile,
+			testNode("org.slf4j:slf4j-api:2.0.9", ScopeCompile),
+			testNode("com.google.guava:guava:32.1.3-jre", ScopeCompile),
+		))
+	}
+	g := root.Graph()
+	assert.Len(t, g.Nodes, 503)
+	assert.Len(t, g.Edges, 1500)
+
+	// nodes added to a graph built by hand are indexed too
+	g = &Graph{Nodes: []GraphNode{{Id: "g:a:1.0", GroupId: "g", Label: "g:a:1.0"}}}
+	g.addNode(Coordinates{GroupId: "g", ArtifactId: "a", Version: "1.0"})
+	assert.Len(t, g.Nodes, 1)
+}
+
+func TestGraph_Write(t *testing.T) {
+	g := testGraph().Graph().Filter("com.example")
+	g.Edges[0].Optional = true
+
+	var buf bytes.Buffer
+	assert.NoError(t, g.WriteDOT(&buf))
+	assert.Contains(t, buf.String(), `"com.example:app:1.0" -> "com.example:lib-a:1.0" [label="compile (optional)", kind="dependency", scope="compile", optional="true", style=dashed];`)
+
+	buf.Reset()
+	assert.NoError(t, g.WriteMermaid(&buf))
+	assert.Contains(t, buf.String(), "graph LR\n  n0[\"com.example:app:1.0\"]\n")
+	assert.Contains(t, buf.String(), "  n0 -.->|compile (optional)| n1\n")
+	assert.Contains(t, buf.String(), "  n0 -->|runtime| n2\n")
+
+	buf.Reset()
+	assert.NoError(t, g.WriteGraphML(&buf))
+	var doc struct {
+		Nodes []struct {
+			Id string `xml:"id,attr"`
+		} `xml:"graph>node"`
+		Edges []struct {
+			Source string `xml:"source,attr"`
+		} `xml:"graph>edge"`
+	}
+	assert.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+	assert.Len(t, doc.Nodes, 3)
+	assert.Len(t, doc.Edges, 2)
+	assert.Contains(t, buf.String(), `<data key="scope">runtime</data>`)
+}
+
+func TestMatchWildcard(t *testing.T) {
+	assert.True(t, matchWildcard("org.apache.*", "org.apache.commons"))
+	assert.False(t, matchWildcard("org.apache.*", "org.apachex"))
+	assert.True(t, matchWildcard("*", "anything"))
+	assert.True(t, matchWildcard("com.*.core", "com.example.core"))
+	assert.False(t, matchWildcard("com.*.core", "com.example.api"))
+	assert.True(t, matchWildcard("junit", "junit"))
+}
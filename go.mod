@@ -7,4 +7,5 @@ require (
 	github.com/elliotchance/orderedmap v1.4.0
 	github.com/stretchr/testify v1.7.0
 	github.com/subchen/go-xmldom v1.1.2
+	gopkg.in/yaml.v3 v3.0.0-20200313102051-9f266ea9e77c
 )
@@ -0,0 +1,147 @@
+package mvnparse
+
+import (
+	"strings"
+)
+
+const (
+	ScopeCompile  = "compile"
+	ScopeProvided = "provided"
+	ScopeRuntime  = "runtime"
+	ScopeTest     = "test"
+	ScopeSystem   = "system"
+	ScopeImport   = "import"
+)
+
+// NodeState tells whether a node of a verbose dependency graph made it into
+// the resolution result, and why not.
+type NodeState int
+
+const (
+	NodeIncluded NodeState = iota
+	NodeOmittedForDuplicate
+	NodeOmittedForConflict
+	NodeOmittedForCycle
+)
+
+// DependencyNode is a node of a resolved dependency graph. The root node is
+// the project itself, its Dependency carrying the project coordinates and
+// packaging as type. Other nodes carry the effective dependency: version and
+// scope after dependency management and mediation. A verbose graph also
+// keeps the nodes that lost mediation, with their State set accordingly.
+type DependencyNode struct {
+	Dependency Dependency
+	// Project is the POM of the artifact, when it has been loaded.
+	Project *Project
+	// File is the resolved artifact file, when known.
+	File string
+
+	PremanagedVersion string
+	PremanagedScope   string
+	// OriginalScope is the scope before it was widened by mediation.
+	OriginalScope string
+
+	State NodeState
+	// Related is the node kept in place of an omitted one.
+	Related *DependencyNode
+
+	Children []*DependencyNode
+}
+
+// NewProjectNode returns a root node for p.
+func NewProjectNode(p *Project) *DependencyNode {
+	return &DependencyNode{Dependency: p.Coordinates().ToDependency(), Project: p}
+}
+
+// Coordinates returns the project coordinates, inheriting groupId and version
+// from the parent when they are not declared.
+func (p *Project) Coordinates() Coordinates {
+	c := Coordinates{GroupId: p.GroupId, ArtifactId: p.ArtifactId, Version: p.Version, Type: p.Packaging}
+	if p.Parent != nil {
+		if c.GroupId == "" {
+			c.GroupId = p.Parent.GroupId
+		}
+		if c.Version == "" {
+			c.Version = p.Parent.Version
+		}
+	}
+	if c.Type == "" {
+		c.Type = "jar"
+	}
+	return c
+}
+
+func (n *DependencyNode) Coordinates() Coordinates {
+	return n.Dependency.Coordinates()
+}
+
+func (n *DependencyNode) Included() bool {
+	return n.State == NodeIncluded
+}
+
+func (n *DependencyNode) Optional() bool {
+	return strings.TrimSpace(n.Dependency.Optional) == "true"
+}
+
+// Scope returns the scope of the node, compile when not set. The root node
+// has no scope.
+func (n *DependencyNode) Scope() string {
+	if n.Dependency.Scope == "" {
+		return ScopeCompile
+	}
+	return n.Dependency.Scope
+}
+
+// ArtifactString formats the node like Maven does, g:a:type[:classifier]:v
+// followed by :scope for non root nodes.
+func (n *DependencyNode) ArtifactString(root bool) string {
+	d := n.Dependency
+	parts := []string{d.GroupId, d.ArtifactId, n.Coordinates().TypeOrDefault()}
+	if d.Classifier != "" {
+		parts = append(parts, d.Classifier)
+	}
+	parts = append(parts, d.Version)
+	if !root {
+		parts = append(parts, n.Scope())
+	}
+	return strings.Join(parts, ":")
+}
+
+// Walk visits n and its descendants depth first, in declaration order. path
+// holds the nodes from the root down to node, both included. Children of a
+// node are skipped when fn returns false.
+func (n *DependencyNode) Walk(fn func(node *DependencyNode, path []*DependencyNode) bool) {
+	n.walk(nil, fn)
+}
+
+func (n *DependencyNode) walk(path []*DependencyNode, fn func(*DependencyNode, []*DependencyNode) bool) {
+	path = append(path[:len(path):len(path)], n)
+	if !fn(n, path) {
+		return
+	}
+	for _, child := range n.Children {
+		child.walk(path, fn)
+	}
+}
+
+// FormatPath formats a path returned by Walk as g:a:v -> g:a:v -> ...
+func FormatPath(path []*DependencyNode) string {
+	parts := make([]string, 0, len(path))
+	for _, node := range path {
+		parts = append(parts, node.Dependency.GroupId+":"+node.Dependency.ArtifactId+":"+node.Dependency.Version)
+	}
+	return strings.Join(parts, " -> ")
+}
+
+// loadProject returns the POM of the node, reading it from repo when it has
+// not been loaded. Nil is returned when it is not available.
+func (n *DependencyNode) loadProject(repo *LocalRepository) *Project {
+	if n.Project != nil || repo == nil {
+		return n.Project
+	}
+	p, err := repo.Project(n.Coordinates())
+	if err != nil {
+		return nil
+	}
+	return p
+}
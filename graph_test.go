@@ -0,0 +1,66 @@
+package mvnparse
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testNode(coordinates, scope string, children ...*DependencyNode) *DependencyNode {
+	c, err := ParseCoordinates(coordinates)
+	if err != nil {
+		panic(err)
+	}
+	d := c.ToDependency()
+	d.Scope = scope
+	return &DependencyNode{Dependency: d, Children: children}
+}
+
+// testGraph returns a verbose graph of com.example:app where guava 31.1-jre
+// wins over the 30.0-jre requested through lib-b.
+func testGraph() *DependencyNode {
+	guava := testNode("com.google.guava:guava:31.1-jre", ScopeCompile)
+	guava.PremanagedVersion = "31.0-jre"
+	conflict := testNode("com.google.guava:guava:30.0-jre", ScopeCompile)
+	conflict.State = NodeOmittedForConflict
+	conflict.Related = guava
+	duplicate := testNode("org.slf4j:slf4j-api:2.0.9", ScopeCompile)
+	duplicate.State = NodeOmittedForDuplicate
+
+	root := &DependencyNode{Dependency: Dependency{GroupId: "com.example", ArtifactId: "app", Version: "1.0", Type: "jar"}}
+	root.Children = []*DependencyNode{
+		testNode("com.example:lib-a:1.0", ScopeCompile,
+			guava,
+			testNode("org.slf4j:slf4j-api:2.0.9", ScopeCompile),
+		),
+		testNode("com.example:lib-b:2.0", ScopeRuntime,
+			conflict,
+			duplicate,
+		),
+		testNode("junit:junit:4.13.2", ScopeTest,
+			testNode("org.hamcrest:hamcrest-core:1.3", ScopeTest),
+		),
+	}
+	return root
+}
+
+func TestProject_Coordinates(t *testing.T) {
+	p := &Project{ArtifactId: "child", Parent: &Parent{GroupId: "g", ArtifactId: "parent", Version: "1.0"}}
+	assert.Equal(t, "g:child:1.0", p.Coordinates().String())
+	p.Packaging = "pom"
+	assert.Equal(t, "g:child:pom:1.0", p.Coordinates().String())
+}
+
+func TestDependencyNode_Walk(t *testing.T) {
+	var paths []string
+	testGraph().Walk(func(node *DependencyNode, path []*DependencyNode) bool {
+		if node.Dependency.ArtifactId == "guava" {
+			paths = append(paths, FormatPath(path))
+		}
+		return node.Included()
+	})
+	assert.Equal(t, []string{
+		"com.example:app:1.0 -> com.example:lib-a:1.0 -> com.google.guava:guava:31.1-jre",
+		"com.example:app:1.0 -> com.example:lib-b:2.0 -> com.google.guava:guava:30.0-jre",
+	}, paths)
+}
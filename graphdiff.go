@@ -0,0 +1,152 @@
+package mvnparse
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// DependencyChange is an artifact added to, removed from or changed between
+// two resolved graphs. Path leads to the artifact in the graph after the
+// change, or before it for a removed artifact.
+type DependencyChange struct {
+	Kind ChangeKind
+	// Key is the management key of the artifact.
+	Key         string
+	FromVersion string
+	ToVersion   string
+	FromScope   string
+	ToScope     string
+	Path        []*DependencyNode
+}
+
+// Direct reports whether the artifact is a direct dependency of the root.
+func (c DependencyChange) Direct() bool {
+	return len(c.Path) == 2
+}
+
+type GraphDiff struct {
+	Changes []DependencyChange
+}
+
+type resolvedArtifact struct {
+	node *DependencyNode
+	path []*DependencyNode
+}
+
+// resolvedArtifacts returns the included artifacts of the graph rooted at
+// n, the root excluded, keyed by management key, in walk order.
+func resolvedArtifacts(n *DependencyNode) ([]string, map[string]resolvedArtifact) {
+	var keys []string
+	artifacts := map[string]resolvedArtifact{}
+	n.Walk(func(node *DependencyNode, path []*DependencyNode) bool {
+		if !node.Included() {
+			return false
+		}
+		if len(path) == 1 {
+			return true
+		}
+		key := node.Dependency.ManagementKey()
+		if _, ok := artifacts[key]; !ok {
+			keys = append(keys, key)
+			artifacts[key] = resolvedArtifact{node, path}
+		}
+		return true
+	})
+	return keys, artifacts
+}
+
+// DiffGraphs compares the artifacts resolved before and after a change:
+// removed artifacts in the order of before, then added and changed ones in
+// the order of after.
+func DiffGraphs(before, after *DependencyNode) *GraphDiff {
+	beforeKeys, beforeArtifacts := resolvedArtifacts(before)
+	afterKeys, afterArtifacts := resolvedArtifacts(after)
+	diff := &GraphDiff{}
+	for _, key := range beforeKeys {
+		if _, ok := afterArtifacts[key]; !ok {
+			a := beforeArtifacts[key]
+			diff.Changes = append(diff.Changes, DependencyChange{
+				Kind:        ChangeRemoved,
+				Key:         key,
+				FromVersion: a.node.Dependency.Version,
+				FromScope:   a.node.Scope(),
+				Path:        a.path,
+			})
+		}
+	}
+	for _, key := range afterKeys {
+		a := afterArtifacts[key]
+		c := DependencyChange{Key: key, ToVersion: a.node.Dependency.Version, ToScope: a.node.Scope(), Path: a.path}
+		b, ok := beforeArtifacts[key]
+		if !ok {
+			c.Kind = ChangeAdded
+			diff.Changes = append(diff.Changes, c)
+			continue
+		}
+		c.FromVersion, c.FromScope = b.node.Dependency.Version, b.node.Scope()
+		if c.FromVersion != c.ToVersion || c.FromScope != c.ToScope {
+			c.Kind = ChangeModified
+			diff.Changes = append(diff.Changes, c)
+		}
+	}
+	return diff
+}
+
+func (d *GraphDiff) filter(kind ChangeKind) []DependencyChange {
+	var changes []DependencyChange
+	for _, c := range d.Changes {
+		if c.Kind == kind {
+			changes = append(changes, c)
+		}
+	}
+	return changes
+}
+
+func (d *GraphDiff) Added() []DependencyChange {
+	return d.filter(ChangeAdded)
+}
+
+func (d *GraphDiff) Removed() []DependencyChange {
+	return d.filter(ChangeRemoved)
+}
+
+func (d *GraphDiff) Changed() []DependencyChange {
+	return d.filter(ChangeModified)
+}
+
+func countTransitive(changes []DependencyChange) int {
+	n := 0
+	for _, c := range changes {
+		if !c.Direct() {
+			n++
+		}
+	}
+	return n
+}
+
+// Text summarizes the diff then lists every change with its path.
+func (d *GraphDiff) Text() string {
+	var buf bytes.Buffer
+	added, removed := d.Added(), d.Removed()
+	fmt.Fprintf(&buf, "%d added (%d transitive), %d removed (%d transitive), %d changed\n",
+		len(added), countTransitive(added), len(removed), countTransitive(removed), len(d.Changed()))
+	for _, c := range d.Changes {
+		switch c.Kind {
+		case ChangeAdded:
+			fmt.Fprintf(&buf, "+ %s %s (%s)\n", c.Key, c.ToVersion, c.ToScope)
+		case ChangeRemoved:
+			fmt.Fprintf(&buf, "- %s %s (%s)\n", c.Key, c.FromVersion, c.FromScope)
+		default:
+			fmt.Fprintf(&buf, "~ %s", c.Key)
+			if c.FromVersion != c.ToVersion {
+				fmt.Fprintf(&buf, " %s → %s", c.FromVersion, c.ToVersion)
+			}
+			if c.FromScope != c.ToScope {
+				fmt.Fprintf(&buf, " scope %s → %s", c.FromScope, c.ToScope)
+			}
+			buf.WriteByte('\n')
+		}
+		fmt.Fprintf(&buf, "    via %s\n", FormatPath(c.Path))
+	}
+	return buf.String()
+}
@@ -0,0 +1,102 @@
+package mvnparse
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffGraphs(t *testing.T) {
+	before := testGraph()
+	after := testGraph()
+	libA := after.Children[0]
+	libA.Children[0].Dependency.Version = "32.1.3-jre"
+	libA.Children = append(libA.Children, testNode("com.google.guava:failureaccess:1.0.1", ScopeCompile))
+	after.Children[1].Dependency.Scope = ScopeCompile
+	after.Children[2] = testNode("org.junit.jupiter:junit-jupiter:5.10.0", ScopeTest)
+
+	diff := DiffGraphs(before, after)
+	assert.Len(t, diff.Added(), 2)
+	assert.Len(t, diff.Removed(), 2)
+	assert.Len(t, diff.Changed(), 2)
+	assert.Equal(t, `2 added (1 transitive), 2 removed (1 transitive), 2 changed
+- junit:junit:jar 4.13.2 (test)
+    via com.example:app:1.0 -> junit:junit:4.13.2
+- org.hamcrest:hamcrest-core:jar 1.3 (test)
+    via com.example:app:1.0 -> junit:junit:4.13.2 -> org.hamcrest:hamcrest-core:1.3
+~ com.google.guava:guava:jar 31.1-jre → 32.1.3-jre
+    via com.example:app:1.0 -> com.example:lib-a:1.0 -> com.google.guava:guava:32.1.3-jre
++ com.google.guava:failureaccess:jar 1.0.1 (compile)
+    via com.example:app:1.0 -> com.example:lib-a:1.0 -> com.google.guava:failureaccess:1.0.1
+~ com.example:lib-b:jar scope runtime → compile
+    via com.example:app:1.0 -> com.example:lib-b:2.0
++ org.junit.jupiter:junit-jupiter:jar 5.10.0 (test)
+    via com.example:app:1.0 -> org.junit.jupiter:junit-jupiter:5.10.0
+`, diff.Text())
+
+	assert.Empty(t, DiffGraphs(testGraph(), testGraph()).Changes)
+}
+
+func TestDiffGraphs_Transitive(t *testing.T) {
+	after := testGraph()
+	libA := after.Children[0]
+	libA.Children[0].Dependency.Version = "32.1.3-jre"
+	libA.Children[1].Dependency.Scope = ScopeRuntime
+
+	diff := DiffGraphs(testGraph(), after)
+	assert.Len(t, diff.Changed(), 2)
+	for _, c := range diff.Changes {
+		assert.False(t, c.Direct(), c.Key)
+	}
+	assert.Equal(t, `0 added (0 transitive), 0 removed (0 transitive), 2 changed
+~ com.google.guava:guava:jar 31.1-jre → 32.1.3-jre
+    via com.example:app:1.0 -> com.example:lib-a:1.0 -> com.google.guava:guava:32.1.3-jre
+~ org.slf4j:slf4j-api:jar scope compile → runtime
+    via com.example:app:1.0 -> com.example:lib-a:1.0 -> org.slf4j:slf4j-api:2.0.9
+`, diff.Text())
+
+	// a scope change of a direct dependency alone
+	after = testGraph()
+	after.Children[2].Dependency.Scope = ScopeCompile
+	diff = DiffGraphs(testGraph(), after)
+	assert.Equal(t, []DependencyChange{{
+		Kind:        ChangeModified,
+		Key:         "junit:junit:jar",
+		FromVersion: "4.13.2",
+		ToVersion:   "4.13.2",
+		FromScope:   ScopeTest,
+		ToScope:     ScopeCompile,
+		Path:        []*DependencyNode{after, after.Children[2]},
+	}}, diff.Changes)
+	assert.True(t, diff.Changes[0].Direct())
+}
+
+func TestDiffGraphs_States(t *testing.T) {
+	before := testGraph()
+	after := testGraph()
+	libA, libB := after.Children[0], after.Children[1]
+	// guava 30.0-jre now wins the conflict
+	libA.Children[0].State = NodeOmittedForConflict
+	libA.Children[0].Related = libB.Children[0]
+	libB.Children[0].State = NodeIncluded
+	libB.Children[0].Related = nil
+	// slf4j is now included through lib-b, at the same version
+	libA.Children[1].State = NodeOmittedForDuplicate
+	libB.Children[1].State = NodeIncluded
+	// hamcrest is no longer resolved at all
+	after.Children[2].Children[0].State = NodeOmittedForConflict
+
+	diff := DiffGraphs(before, after)
+	assert.Equal(t, `0 added (0 transitive), 1 removed (1 transitive), 1 changed
+- org.hamcrest:hamcrest-core:jar 1.3 (test)
+    via com.example:app:1.0 -> junit:junit:4.13.2 -> org.hamcrest:hamcrest-core:1.3
+~ com.google.guava:guava:jar 31.1-jre → 30.0-jre
+    via com.example:app:1.0 -> com.example:lib-b:2.0 -> com.google.guava:guava:30.0-jre
+`, diff.Text())
+
+	// the other way around, the omitted artifact is added
+	diff = DiffGraphs(after, before)
+	assert.Len(t, diff.Added(), 1)
+	assert.Equal(t, "org.hamcrest:hamcrest-core:jar", diff.Added()[0].Key)
+	assert.Empty(t, diff.Removed())
+}
@@ -0,0 +1,134 @@
+package mvnparse
+
+import (
+	"encoding/xml"
+	"strings"
+	"sync"
+)
+
+const artifactHandlerRole = "org.apache.maven.artifact.handler.ArtifactHandler"
+
+// ArtifactHandler describes how a dependency type maps to an artifact file
+// and whether it belongs on the classpath, like Maven's ArtifactHandler.
+type ArtifactHandler struct {
+	Type                 string
+	Extension            string
+	Classifier           string
+	Packaging            string
+	Language             string
+	AddedToClasspath     bool
+	IncludesDependencies bool
+}
+
+// ArtifactHandlers is a registry of artifact handlers keyed by type.
+type ArtifactHandlers struct {
+	mu       sync.RWMutex
+	handlers map[string]ArtifactHandler
+}
+
+// DefaultArtifactHandlers holds the handlers shipped with Maven, plus any
+// registered by the program.
+var DefaultArtifactHandlers = NewArtifactHandlers()
+
+// NewArtifactHandlers returns a registry holding Maven's default handlers.
+func NewArtifactHandlers() *ArtifactHandlers {
+	r := &ArtifactHandlers{handlers: map[string]ArtifactHandler{}}
+	for _, h := range []ArtifactHandler{
+		{Type: "pom", Language: "none"},
+		{Type: "jar", Language: "java", AddedToClasspath: true},
+		{Type: "test-jar", Extension: "jar", Packaging: "jar", Classifier: "tests", Language: "java", AddedToClasspath: true},
+		{Type: "maven-plugin", Extension: "jar", Language: "java", AddedToClasspath: true},
+		{Type: "ejb", Extension: "jar", Language: "java", AddedToClasspath: true},
+		{Type: "ejb-client", Extension: "jar", Packaging: "ejb", Classifier: "client", Language: "java", AddedToClasspath: true},
+		{Type: "war", Language: "java", IncludesDependencies: true},
+		{Type: "ear", Language: "java", IncludesDependencies: true},
+		{Type: "rar", Language: "java", IncludesDependencies: true},
+		{Type: "java-source", Extension: "jar", Classifier: "sources", Language: "java"},
+		{Type: "javadoc", Extension: "jar", Classifier: "javadoc", Language: "java", AddedToClasspath: true},
+	} {
+		r.Register(h)
+	}
+	return r
+}
+
+// Register adds or replaces the handler for h.Type. Empty extension and
+// packaging default to the type, an empty language to none.
+func (r *ArtifactHandlers) Register(h ArtifactHandler) {
+	if h.Extension == "" {
+		h.Extension = h.Type
+	}
+	if h.Packaging == "" {
+		h.Packaging = h.Type
+	}
+	if h.Language == "" {
+		h.Language = "none"
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handlers[h.Type] = h
+}
+
+// Get returns the handler for typ. Unknown types get a handler using the
+// type as extension, as Maven does.
+func (r *ArtifactHandlers) Get(typ string) ArtifactHandler {
+	if typ == "" {
+		typ = "jar"
+	}
+	r.mu.RLock()
+	h, ok := r.handlers[typ]
+	r.mu.RUnlock()
+	if ok {
+		return h
+	}
+	return ArtifactHandler{Type: typ, Extension: typ, Packaging: typ, Language: "none"}
+}
+
+// RegisterComponents registers the artifact handlers declared in a plexus
+// components.xml, which is how build extensions and plugins with
+// <extensions>true</extensions> contribute new types.
+func (r *ArtifactHandlers) RegisterComponents(xmlStr string) error {
+	type configuration struct {
+		Type                 string `xml:"type"`
+		Extension            string `xml:"extension"`
+		Classifier           string `xml:"classifier"`
+		Packaging            string `xml:"packaging"`
+		Language             string `xml:"language"`
+		AddedToClasspath     string `xml:"addedToClasspath"`
+		IncludesDependencies string `xml:"includesDependencies"`
+	}
+	type component struct {
+		Role          string        `xml:"role"`
+		RoleHint      string        `xml:"role-hint"`
+		Configuration configuration `xml:"configuration"`
+	}
+	var set struct {
+		Components []component `xml:"components>component"`
+	}
+	err := xml.Unmarshal([]byte(xmlStr), &set)
+	if err != nil {
+		return err
+	}
+	for _, c := range set.Components {
+		if strings.TrimSpace(c.Role) != artifactHandlerRole {
+			continue
+		}
+		conf := c.Configuration
+		typ := strings.TrimSpace(conf.Type)
+		if typ == "" {
+			typ = strings.TrimSpace(c.RoleHint)
+		}
+		if typ == "" {
+			continue
+		}
+		r.Register(ArtifactHandler{
+			Type:                 typ,
+			Extension:            strings.TrimSpace(conf.Extension),
+			Classifier:           strings.TrimSpace(conf.Classifier),
+			Packaging:            strings.TrimSpace(conf.Packaging),
+			Language:             strings.TrimSpace(conf.Language),
+			AddedToClasspath:     strings.TrimSpace(conf.AddedToClasspath) == "true",
+			IncludesDependencies: strings.TrimSpace(conf.IncludesDependencies) == "true",
+		})
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package mvnparse
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArtifactHandlers_Defaults(t *testing.T) {
+	r := NewArtifactHandlers()
+	h := r.Get("test-jar")
+	assert.Equal(t, "jar", h.Extension)
+	assert.Equal(t, "tests", h.Classifier)
+	assert.True(t, h.AddedToClasspath)
+
+	h = r.Get("war")
+	assert.Equal(t, "war", h.Extension)
+	assert.False(t, h.AddedToClasspath)
+	assert.True(t, h.IncludesDependencies)
+
+	h = r.Get("")
+	assert.Equal(t, "jar", h.Type)
+
+	h = r.Get("zip")
+	assert.Equal(t, "zip", h.Extension)
+	assert.Equal(t, "none", h.Language)
+}
+
+func TestArtifactHandlers_RegisterComponents(t *testing.T) {
+	components := `<component-set>
+  <components>
+    <component>
+      <role>org.apache.maven.artifact.handler.ArtifactHandler</role>
+      <role-hint>bundle</role-hint>
+      <implementation>org.apache.maven.artifact.handler.DefaultArtifactHandler</implementation>
+      <configuration>
+        <extension>jar</extension>
+        <language>java</language>
+        <addedToClasspath>true</addedToClasspath>
+      </configuration>
+    </component>
+    <component>
+      <role>org.apache.maven.lifecycle.mapping.LifecycleMapping</role>
+      <role-hint>bundle</role-hint>
+    </component>
+  </components>
+</component-set>`
+	r := NewArtifactHandlers()
+	assert.NoError(t, r.RegisterComponents(components))
+	h := r.Get("bundle")
+	assert.Equal(t, "jar", h.Extension)
+	assert.Equal(t, "bundle", h.Packaging)
+	assert.True(t, h.AddedToClasspath)
+
+	c := Coordinates{GroupId: "g", ArtifactId: "a", Version: "1", Type: "bundle"}
+	assert.Equal(t, "bundle", c.Extension())
+	assert.Equal(t, "jar", c.ExtensionWith(r))
+}
+
+func TestLocalRepository_Path(t *testing.T) {
+	repo := NewLocalRepository("/repo")
+	c := Coordinates{GroupId: "org.example", ArtifactId: "lib", Version: "1.0", Type: "test-jar"}
+	assert.Equal(t, filepath.FromSlash("/repo/org/example/lib/1.0/lib-1.0-tests.jar"), repo.Path(c))
+	assert.Equal(t, filepath.FromSlash("/repo/org/example/lib/1.0/lib-1.0.pom"), repo.PomPath(c))
+
+	c = Coordinates{GroupId: "org.example", ArtifactId: "lib", Version: "1.0-20261018.101010-3"}
+	assert.Equal(t, filepath.FromSlash("/repo/org/example/lib/1.0-SNAPSHOT/lib-1.0-20261018.101010-3.jar"), repo.Path(c))
+
+	assert.Equal(t, filepath.FromSlash("/repo/org/example/lib/maven-metadata-central.xml"), repo.MetadataPath("org.example", "lib", "", "central"))
+	assert.Equal(t, filepath.FromSlash("/repo/org/example/lib/1.0-SNAPSHOT/maven-metadata-local.xml"), repo.MetadataPath("org.example", "lib", "1.0-SNAPSHOT", ""))
+}
@@ -0,0 +1,254 @@
+package mvnparse
+
+import (
+	"regexp"
+	"strings"
+)
+
+// LicenseConfidence tells how reliable the mapping of a license to an SPDX
+// identifier is.
+type LicenseConfidence int
+
+const (
+	LicenseNoMatch LicenseConfidence = iota
+	// LicenseLow matched on keywords only.
+	LicenseLow
+	// LicenseHigh matched a known variant of the license name.
+	LicenseHigh
+	// LicenseExact matched an SPDX identifier or a canonical license URL.
+	LicenseExact
+)
+
+func (c LicenseConfidence) String() string {
+	switch c {
+	case LicenseLow:
+		return "low"
+	case LicenseHigh:
+		return "high"
+	case LicenseExact:
+		return "exact"
+	default:
+		return "none"
+	}
+}
+
+// LicenseMatch is the SPDX identifier, or expression, found for a License.
+type LicenseMatch struct {
+	License    License
+	ID         string
+	Confidence LicenseConfidence
+}
+
+type spdxLicense struct {
+	id    string
+	names []string
+	urls  []string
+}
+
+// spdxLicenses maps the license names and URLs found in POMs of Maven
+// Central to SPDX identifiers. Ids may be expressions for licenses such as
+// CDDL+GPL that are offered as a choice.
+var spdxLicenses = []spdxLicense{
+	{"Apache-2.0",
+		[]string{"Apache License 2.0", "Apache License, Version 2.0", "The Apache Software License, Version 2.0", "The Apache License, Version 2.0", "Apache Software License - Version 2.0", "Apache 2", "Apache 2.0", "Apache-2", "ASL 2.0", "ASF 2.0", "Apache License v2.0", "Apache Public License 2.0"},
+		[]string{"http://www.apache.org/licenses/LICENSE-2.0", "http://www.apache.org/licenses/LICENSE-2.0.txt", "http://www.apache.org/licenses/LICENSE-2.0.html", "https://opensource.org/licenses/Apache-2.0", "http://repository.jboss.org/licenses/apache-2.0.txt"}},
+	{"Apache-1.1",
+		[]string{"Apache License 1.1", "The Apache Software License, Version 1.1", "Apache Software License, Version 1.1"},
+		[]string{"http://www.apache.org/licenses/LICENSE-1.1", "https://opensource.org/licenses/Apache-1.1"}},
+	{"MIT",
+		[]string{"MIT License", "The MIT License", "MIT", "The MIT License (MIT)", "MIT license (also X11)", "Bouncy Castle Licence"},
+		[]string{"https://opensource.org/licenses/MIT", "http://www.opensource.org/licenses/mit-license.php", "https://mit-license.org", "https://www.bouncycastle.org/licence.html"}},
+	{"BSD-2-Clause",
+		[]string{"BSD 2-Clause License", "The BSD 2-Clause License", "Simplified BSD License", "FreeBSD License", "BSD-2-Clause"},
+		[]string{"https://opensource.org/licenses/BSD-2-Clause", "http://www.opensource.org/licenses/bsd-license.php"}},
+	{"BSD-3-Clause",
+		[]string{"BSD 3-Clause License", "The BSD 3-Clause License", "BSD 3-Clause", "New BSD License", "The New BSD License", "Revised BSD License", "Modified BSD License", "BSD License 3", "Eclipse Distribution License - v 1.0", "Eclipse Distribution License v. 1.0", "EDL 1.0"},
+		[]string{"https://opensource.org/licenses/BSD-3-Clause", "http://www.eclipse.org/org/documents/edl-v10.php", "http://www.eclipse.org/org/documents/edl-v10.html", "https://asm.ow2.io/license.html"}},
+	{"EPL-1.0",
+		[]string{"Eclipse Public License 1.0", "Eclipse Public License - v 1.0", "Eclipse Public License v1.0", "EPL 1.0"},
+		[]string{"http://www.eclipse.org/legal/epl-v10.html", "https://opensource.org/licenses/EPL-1.0"}},
+	{"EPL-2.0",
+		[]string{"Eclipse Public License 2.0", "Eclipse Public License - v 2.0", "Eclipse Public License v2.0", "EPL 2.0"},
+		[]string{"https://www.eclipse.org/legal/epl-2.0", "http://www.eclipse.org/legal/epl-v20.html", "https://www.eclipse.org/org/documents/epl-2.0/EPL-2.0.txt", "https://opensource.org/licenses/EPL-2.0"}},
+	{"GPL-2.0-only",
+		[]string{"GNU General Public License, Version 2", "GNU General Public License v2.0", "GPL 2", "GPLv2", "GPL-2.0"},
+		[]string{"https://www.gnu.org/licenses/old-licenses/gpl-2.0.html", "https://opensource.org/licenses/GPL-2.0"}},
+	{"GPL-2.0-only WITH Classpath-exception-2.0",
+		[]string{"GNU General Public License, version 2 with the GNU Classpath Exception", "GPL2 w/ CPE", "GPLv2 with Classpath Exception", "GNU General Public License, version 2, with the Classpath Exception"},
+		[]string{"http://openjdk.java.net/legal/gplv2+ce.html", "https://www.gnu.org/software/classpath/license.html"}},
+	{"GPL-3.0-only",
+		[]string{"GNU General Public License, Version 3", "GNU General Public License v3.0", "GPL 3", "GPLv3", "GPL-3.0"},
+		[]string{"https://www.gnu.org/licenses/gpl-3.0.html", "https://www.gnu.org/licenses/gpl-3.0.txt", "https://opensource.org/licenses/GPL-3.0"}},
+	{"LGPL-2.1-only",
+		[]string{"GNU Lesser General Public License, Version 2.1", "GNU Lesser General Public License v2.1", "LGPL 2.1", "LGPLv2.1", "LGPL-2.1"},
+		[]string{"https://www.gnu.org/licenses/old-licenses/lgpl-2.1.html", "http://www.gnu.org/licenses/lgpl-2.1.html", "https://opensource.org/licenses/LGPL-2.1"}},
+	{"LGPL-3.0-only",
+		[]string{"GNU Lesser General Public License, Version 3", "GNU Lesser General Public License v3.0", "LGPL 3", "LGPLv3", "LGPL-3.0"},
+		[]string{"https://www.gnu.org/licenses/lgpl-3.0.html", "https://www.gnu.org/licenses/lgpl-3.0.txt", "http://www.gnu.org/licenses/lgpl.html", "https://opensource.org/licenses/LGPL-3.0"}},
+	{"MPL-1.1",
+		[]string{"Mozilla Public License 1.1", "Mozilla Public License Version 1.1", "MPL 1.1"},
+		[]string{"http://www.mozilla.org/MPL/MPL-1.1.html", "https://opensource.org/licenses/MPL-1.1"}},
+	{"MPL-2.0",
+		[]string{"Mozilla Public License 2.0", "Mozilla Public License Version 2.0", "MPL 2.0"},
+		[]string{"https://www.mozilla.org/MPL/2.0/", "https://opensource.org/licenses/MPL-2.0"}},
+	{"CDDL-1.0",
+		[]string{"Common Development and Distribution License 1.0", "Common Development and Distribution License (CDDL) v1.0", "CDDL 1.0", "CDDL License"},
+		[]string{"https://opensource.org/licenses/CDDL-1.0", "http://www.opensource.org/licenses/cddl1.php"}},
+	{"CDDL-1.1",
+		[]string{"Common Development and Distribution License 1.1", "CDDL 1.1"},
+		[]string{"https://spdx.org/licenses/CDDL-1.1.html"}},
+	{"CDDL-1.1 OR GPL-2.0-only WITH Classpath-exception-2.0",
+		[]string{"CDDL + GPLv2 with classpath exception", "CDDL+GPL License", "CDDL/GPLv2+CE", "Dual license consisting of the CDDL v1.1 and GPL v2"},
+		[]string{"https://oss.oracle.com/licenses/CDDL+GPL-1.1", "https://glassfish.java.net/public/CDDL+GPL_1_1.html", "https://github.com/javaee/javax.annotation/blob/master/LICENSE"}},
+	{"CC0-1.0",
+		[]string{"CC0", "CC0 1.0 Universal", "Creative Commons Zero", "Public Domain, per Creative Commons CC0"},
+		[]string{"https://creativecommons.org/publicdomain/zero/1.0/", "https://creativecommons.org/publicdomain/zero/1.0/legalcode"}},
+	{"Unlicense",
+		[]string{"The Unlicense", "Unlicense"},
+		[]string{"https://unlicense.org/", "https://unlicense.org/UNLICENSE"}},
+	{"ISC",
+		[]string{"ISC License", "ISC"},
+		[]string{"https://opensource.org/licenses/ISC"}},
+}
+
+// licenseKeywords are tried in order when neither name nor URL are known.
+var licenseKeywords = []struct {
+	words []string
+	id    string
+}{
+	{[]string{"apache", "2"}, "Apache-2.0"},
+	{[]string{"affero", "3"}, "AGPL-3.0-only"},
+	{[]string{"lesser", "3"}, "LGPL-3.0-only"},
+	{[]string{"lgpl", "3"}, "LGPL-3.0-only"},
+	{[]string{"lesser", "2", "1"}, "LGPL-2.1-only"},
+	{[]string{"lgpl", "2", "1"}, "LGPL-2.1-only"},
+	{[]string{"classpath", "exception"}, "GPL-2.0-only WITH Classpath-exception-2.0"},
+	{[]string{"general", "public", "3"}, "GPL-3.0-only"},
+	{[]string{"general", "public", "2"}, "GPL-2.0-only"},
+	{[]string{"eclipse", "public", "2"}, "EPL-2.0"},
+	{[]string{"eclipse", "public"}, "EPL-1.0"},
+	{[]string{"eclipse", "distribution"}, "BSD-3-Clause"},
+	{[]string{"mozilla", "2"}, "MPL-2.0"},
+	{[]string{"mozilla", "1", "1"}, "MPL-1.1"},
+	{[]string{"cddl", "gpl"}, "CDDL-1.1 OR GPL-2.0-only WITH Classpath-exception-2.0"},
+	{[]string{"cddl", "1", "1"}, "CDDL-1.1"},
+	{[]string{"cddl"}, "CDDL-1.0"},
+	{[]string{"common", "development", "distribution"}, "CDDL-1.0"},
+	{[]string{"bsd", "2"}, "BSD-2-Clause"},
+	{[]string{"bsd"}, "BSD-3-Clause"},
+	{[]string{"mit"}, "MIT"},
+	{[]string{"cc0"}, "CC0-1.0"},
+}
+
+var (
+	licenseNames = map[string]string{}
+	licenseURLs  = map[string]string{}
+	licenseIds   = map[string]string{}
+
+	licenseWordSeparator = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+func init() {
+	for _, l := range spdxLicenses {
+		licenseIds[strings.ToLower(l.id)] = l.id
+		for _, name := range l.names {
+			licenseNames[normalizeLicenseName(name)] = l.id
+		}
+		for _, url := range l.urls {
+			licenseURLs[normalizeLicenseURL(url)] = l.id
+		}
+	}
+}
+
+// normalizeLicenseName lower cases the name, drops punctuation and noise
+// words, so that "The Apache License, Version 2.0" and "apache license 2.0"
+// compare equal.
+func normalizeLicenseName(name string) string {
+	words := licenseWordSeparator.Split(strings.ToLower(name), -1)
+	kept := make([]string, 0, len(words))
+	for _, w := range words {
+		switch w {
+		case "", "the", "version", "v", "licence", "license":
+			continue
+		}
+		kept = append(kept, w)
+	}
+	return strings.Join(kept, " ")
+}
+
+func normalizeLicenseURL(url string) string {
+	url = strings.ToLower(strings.TrimSpace(url))
+	for _, prefix := range []string{"https://", "http://", "www."} {
+		url = strings.TrimPrefix(url, prefix)
+	}
+	url = strings.TrimRight(url, "/")
+	for _, suffix := range []string{".txt", ".html", ".php"} {
+		url = strings.TrimSuffix(url, suffix)
+	}
+	return url
+}
+
+// NormalizeLicense maps a POM license to an SPDX identifier using its name
+// and URL.
+func NormalizeLicense(l License) LicenseMatch {
+	match := LicenseMatch{License: l}
+	name := strings.TrimSpace(l.Name)
+	if id, ok := licenseIds[strings.ToLower(name)]; ok {
+		match.ID, match.Confidence = id, LicenseExact
+		return match
+	}
+	if id, ok := licenseURLs[normalizeLicenseURL(l.URL)]; ok && l.URL != "" {
+		match.ID, match.Confidence = id, LicenseExact
+		return match
+	}
+	if id, ok := licenseNames[normalizeLicenseName(name)]; ok && name != "" {
+		match.ID, match.Confidence = id, LicenseHigh
+		return match
+	}
+	words := map[string]bool{}
+	for _, w := range licenseWordSeparator.Split(strings.ToLower(name+" "+l.URL), -1) {
+		words[w] = true
+	}
+	for _, k := range licenseKeywords {
+		found := true
+		for _, w := range k.words {
+			if !words[w] {
+				found = false
+				break
+			}
+		}
+		if found {
+			match.ID, match.Confidence = k.id, LicenseLow
+			return match
+		}
+	}
+	return match
+}
+
+// EffectiveLicenses returns the licenses of a project given its parent
+// chain, nearest first: licenses are inherited from the closest ancestor
+// declaring some.
+func EffectiveLicenses(chain []*Project) []License {
+	for _, p := range chain {
+		if p != nil && p.Licenses != nil && len(*p.Licenses) > 0 {
+			return *p.Licenses
+		}
+	}
+	return nil
+}
+
+// NormalizeLicenses normalizes the effective licenses of a parent chain.
+func NormalizeLicenses(chain []*Project) []LicenseMatch {
+	licenses := EffectiveLicenses(chain)
+	matches := make([]LicenseMatch, 0, len(licenses))
+	for _, l := range licenses {
+		matches = append(matches, NormalizeLicense(l))
+	}
+	return matches
+}
+
+// licenses returns the normalized effective licenses of the node, reading
+// its POM and parents from repo as needed.
+func (n *DependencyNode) licenses(repo *LocalRepository) []LicenseMatch {
+	return NormalizeLicenses(n.projectChain(repo))
+}
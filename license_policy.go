@@ -0,0 +1,262 @@
+package mvnparse
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+type LicenseAction string
+
+const (
+	LicenseAllow  LicenseAction = "allow"
+	LicenseReview LicenseAction = "review"
+	LicenseDeny   LicenseAction = "deny"
+)
+
+// LicensePolicy decides which licenses dependencies may use.
+type LicensePolicy struct {
+	// Allow, Deny and Review list SPDX identifiers or expressions.
+	Allow  []string `json:"allow,omitempty"`
+	Deny   []string `json:"deny,omitempty"`
+	Review []string `json:"review,omitempty"`
+	// Groups apply an action to every artifact of matching groupIds,
+	// whatever their licenses, e.g. to allow internal artifacts.
+	Groups []LicenseGroupRule `json:"groups,omitempty"`
+	// Exceptions allow specific artifacts.
+	Exceptions []LicenseException `json:"exceptions,omitempty"`
+	// Default applies to licenses listed nowhere, unrecognized licenses and
+	// artifacts without license. Review when empty.
+	Default LicenseAction `json:"default,omitempty"`
+	// IgnoreScopes skips dependencies of these scopes, such as test.
+	IgnoreScopes []string `json:"ignoreScopes,omitempty"`
+}
+
+type LicenseGroupRule struct {
+	// Pattern is a groupId where * is a wildcard.
+	Pattern string        `json:"pattern"`
+	Action  LicenseAction `json:"action"`
+}
+
+type LicenseException struct {
+	// Artifact is a pattern groupId[:artifactId[:version]] with * wildcards.
+	Artifact string `json:"artifact"`
+	// Licenses restricts the exception to these SPDX identifiers.
+	Licenses []string `json:"licenses,omitempty"`
+	Reason   string   `json:"reason,omitempty"`
+}
+
+type LicenseFinding struct {
+	Node     *DependencyNode
+	Path     []*DependencyNode
+	Licenses []LicenseMatch
+	Action   LicenseAction
+	Reason   string
+}
+
+type LicenseReport struct {
+	Findings []LicenseFinding
+}
+
+// Evaluate applies the policy to every artifact of the graph rooted at n,
+// reading POMs missing from the nodes from repo. An artifact declaring
+// several licenses may be used under any of them, so the most permissive
+// outcome wins. Each artifact is reported once, with the first path that
+// pulled it in.
+func (p *LicensePolicy) Evaluate(n *DependencyNode, repo *LocalRepository) *LicenseReport {
+	report := &LicenseReport{}
+	seen := map[string]bool{}
+	n.Walk(func(node *DependencyNode, path []*DependencyNode) bool {
+		if !node.Included() {
+			return false
+		}
+		if len(path) == 1 {
+			return true
+		}
+		if containsString(p.IgnoreScopes, node.Scope()) {
+			return false
+		}
+		key := node.Coordinates().String()
+		if seen[key] {
+			return true
+		}
+		seen[key] = true
+		finding := LicenseFinding{Node: node, Path: path, Licenses: node.licenses(repo)}
+		finding.Action, finding.Reason = p.decide(node, finding.Licenses)
+		report.Findings = append(report.Findings, finding)
+		return true
+	})
+	return report
+}
+
+func (p *LicensePolicy) defaultAction() LicenseAction {
+	if p.Default == "" {
+		return LicenseReview
+	}
+	return p.Default
+}
+
+func (p *LicensePolicy) decide(node *DependencyNode, licenses []LicenseMatch) (LicenseAction, string) {
+	d := node.Dependency
+	for _, e := range p.Exceptions {
+		if !matchArtifact(e.Artifact, Dependency{GroupId: d.GroupId, ArtifactId: d.ArtifactId, Version: d.Version}) {
+			continue
+		}
+		if len(e.Licenses) > 0 && !anyLicense(licenses, e.Licenses) {
+			continue
+		}
+		reason := "exception for " + e.Artifact
+		if e.Reason != "" {
+			reason += ": " + e.Reason
+		}
+		return LicenseAllow, reason
+	}
+	for _, g := range p.Groups {
+		if matchWildcard(g.Pattern, d.GroupId) {
+			return g.Action, "groupId matches " + g.Pattern
+		}
+	}
+	if len(licenses) == 0 {
+		return p.defaultAction(), "no license declared"
+	}
+
+	var best LicenseAction
+	var reason string
+	for _, m := range licenses {
+		action, why := p.licenseAction(m)
+		if best == "" || actionRank(action) < actionRank(best) {
+			best, reason = action, why
+		}
+	}
+	return best, reason
+}
+
+func (p *LicensePolicy) licenseAction(m LicenseMatch) (LicenseAction, string) {
+	if m.ID == "" {
+		return p.defaultAction(), fmt.Sprintf("unrecognized license %q", licenseLabel(m.License))
+	}
+	// a keyword guess is no ground to allow, nor to deny, a license
+	if m.Confidence < LicenseHigh {
+		return p.defaultAction(), fmt.Sprintf("unrecognized license %q, maybe %s", licenseLabel(m.License), m.ID)
+	}
+	if action, ok := p.idAction(m.ID); ok {
+		return action, fmt.Sprintf("%s is %s", m.ID, actionVerb(action))
+	}
+	// a choice between licenses is as good as its best option
+	if parts := strings.Split(m.ID, " OR "); len(parts) > 1 {
+		var best LicenseAction
+		for _, part := range parts {
+			action, ok := p.idAction(strings.TrimSpace(part))
+			if !ok {
+				action = p.defaultAction()
+			}
+			if best == "" || actionRank(action) < actionRank(best) {
+				best = action
+			}
+		}
+		return best, fmt.Sprintf("%s is %s", m.ID, actionVerb(best))
+	}
+	return p.defaultAction(), fmt.Sprintf("%s is not listed in the policy", m.ID)
+}
+
+func (p *LicensePolicy) idAction(id string) (LicenseAction, bool) {
+	switch {
+	case containsFold(p.Deny, id):
+		return LicenseDeny, true
+	case containsFold(p.Review, id):
+		return LicenseReview, true
+	case containsFold(p.Allow, id):
+		return LicenseAllow, true
+	}
+	return "", false
+}
+
+func actionRank(a LicenseAction) int {
+	switch a {
+	case LicenseAllow:
+		return 0
+	case LicenseReview:
+		return 1
+	default:
+		return 2
+	}
+}
+
+func actionVerb(a LicenseAction) string {
+	switch a {
+	case LicenseAllow:
+		return "allowed"
+	case LicenseDeny:
+		return "denied"
+	default:
+		return "subject to review"
+	}
+}
+
+func anyLicense(licenses []LicenseMatch, ids []string) bool {
+	for _, m := range licenses {
+		if m.ID != "" && m.Confidence >= LicenseHigh && containsFold(ids, m.ID) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(values []string, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func licenseLabel(l License) string {
+	if l.Name != "" {
+		return l.Name
+	}
+	return l.URL
+}
+
+func (r *LicenseReport) filter(action LicenseAction) []LicenseFinding {
+	var findings []LicenseFinding
+	for _, f := range r.Findings {
+		if f.Action == action {
+			findings = append(findings, f)
+		}
+	}
+	return findings
+}
+
+// Violations returns the denied artifacts.
+func (r *LicenseReport) Violations() []LicenseFinding {
+	return r.filter(LicenseDeny)
+}
+
+// Reviews returns the artifacts needing a manual review.
+func (r *LicenseReport) Reviews() []LicenseFinding {
+	return r.filter(LicenseReview)
+}
+
+// Text lists the violations then the artifacts to review, with the
+// dependency path that pulled each one in.
+func (r *LicenseReport) Text() string {
+	var buf bytes.Buffer
+	for _, section := range []struct {
+		title    string
+		findings []LicenseFinding
+	}{
+		{"License violations", r.Violations()},
+		{"Licenses to review", r.Reviews()},
+	} {
+		if len(section.findings) == 0 {
+			continue
+		}
+		fmt.Fprintf(&buf, "%s:\n", section.title)
+		for _, f := range section.findings {
+			fmt.Fprintf(&buf, "  %s: %s\n", f.Node.Coordinates(), f.Reason)
+			fmt.Fprintf(&buf, "    via %s\n", FormatPath(f.Path))
+		}
+	}
+	return buf.String()
+}
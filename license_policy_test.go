@@ -0,0 +1,114 @@
+package mvnparse
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func licensedTestGraph() *DependencyNode {
+	root := testGraph()
+	licenses := map[string][]License{
+		"lib-a":         {{Name: "Acme Proprietary"}},
+		"guava":         {{Name: "Apache License 2.0"}},
+		"slf4j-api":     {{Name: "MIT License"}},
+		"lib-b":         {{Name: "GNU General Public License, Version 3"}, {Name: "LGPL-3.0-only"}},
+		"junit":         {{Name: "Eclipse Public License 1.0"}},
+		"hamcrest-core": {{Name: "New BSD License"}},
+	}
+	root.Walk(func(node *DependencyNode, path []*DependencyNode) bool {
+		if l, ok := licenses[node.Dependency.ArtifactId]; ok {
+			node.Project = &Project{Licenses: &l}
+		}
+		return true
+	})
+	return root
+}
+
+func TestLicensePolicy_Evaluate(t *testing.T) {
+	policy := &LicensePolicy{
+		Allow:  []string{"Apache-2.0", "MIT", "BSD-3-Clause"},
+		Deny:   []string{"GPL-3.0-only", "LGPL-3.0-only"},
+		Review: []string{"EPL-1.0"},
+		Groups: []LicenseGroupRule{{Pattern: "com.example", Action: LicenseAllow}},
+	}
+	report := policy.Evaluate(licensedTestGraph(), nil)
+	assert.Len(t, report.Findings, 6)
+	assert.Empty(t, report.Violations())
+	reviews := report.Reviews()
+	assert.Len(t, reviews, 1)
+	assert.Equal(t, "junit", reviews[0].Node.Dependency.ArtifactId)
+	assert.Equal(t, "EPL-1.0 is subject to review", reviews[0].Reason)
+
+	policy.Groups = nil
+	policy.Exceptions = []LicenseException{{Artifact: "com.example:lib-a", Reason: "internal"}}
+	policy.IgnoreScopes = []string{ScopeTest}
+	report = policy.Evaluate(licensedTestGraph(), nil)
+	assert.Len(t, report.Findings, 4)
+	assert.Empty(t, report.Reviews())
+	violations := report.Violations()
+	assert.Len(t, violations, 1)
+	assert.Equal(t, "lib-b", violations[0].Node.Dependency.ArtifactId)
+	assert.Equal(t, "com.example:app:1.0 -> com.example:lib-b:2.0", FormatPath(violations[0].Path))
+	assert.Equal(t, LicenseAllow, report.Findings[0].Action)
+	assert.Equal(t, "exception for com.example:lib-a: internal", report.Findings[0].Reason)
+
+	assert.Equal(t, `License violations:
+  com.example:lib-b:2.0: GPL-3.0-only is denied
+    via com.example:app:1.0 -> com.example:lib-b:2.0
+`, report.Text())
+}
+
+func TestLicensePolicy_Decide(t *testing.T) {
+	policy := &LicensePolicy{
+		Allow:   []string{"CDDL-1.1"},
+		Deny:    []string{"GPL-2.0-only WITH Classpath-exception-2.0"},
+		Default: LicenseDeny,
+	}
+	node := testNode("javax.annotation:javax.annotation-api:1.3.2", ScopeCompile)
+
+	action, _ := policy.decide(node, []LicenseMatch{NormalizeLicense(License{Name: "CDDL + GPLv2 with classpath exception"})})
+	assert.Equal(t, LicenseAllow, action)
+
+	action, reason := policy.decide(node, nil)
+	assert.Equal(t, LicenseDeny, action)
+	assert.Equal(t, "no license declared", reason)
+
+	action, reason = policy.decide(node, []LicenseMatch{NormalizeLicense(License{Name: "Acme"})})
+	assert.Equal(t, LicenseDeny, action)
+	assert.Equal(t, `unrecognized license "Acme"`, reason)
+
+	policy.Exceptions = []LicenseException{{Artifact: "javax.*", Licenses: []string{"MIT"}}}
+	action, _ = policy.decide(node, []LicenseMatch{NormalizeLicense(License{Name: "Acme"})})
+	assert.Equal(t, LicenseDeny, action)
+	action, _ = policy.decide(node, []LicenseMatch{NormalizeLicense(License{Name: "MIT"})})
+	assert.Equal(t, LicenseAllow, action)
+}
+
+func TestLicensePolicy_Decide_LowConfidence(t *testing.T) {
+	policy := &LicensePolicy{Allow: []string{"MIT", "BSD-3-Clause"}, Deny: []string{"GPL-3.0-only"}}
+	node := testNode("org.example:lib:1.0", ScopeCompile)
+
+	for name, guess := range map[string]string{
+		"BSD style":                   "BSD-3-Clause",
+		"Permissive (similar to mit)": "MIT",
+		"GNU General Public 3 or so":  "GPL-3.0-only",
+	} {
+		m := NormalizeLicense(License{Name: name})
+		assert.Equal(t, LicenseLow, m.Confidence, name)
+		action, reason := policy.decide(node, []LicenseMatch{m})
+		assert.Equal(t, LicenseReview, action, name)
+		assert.Equal(t, fmt.Sprintf("unrecognized license %q, maybe %s", name, guess), reason)
+	}
+
+	// a recognized license still wins over a guess
+	action, _ := policy.decide(node, []LicenseMatch{NormalizeLicense(License{Name: "BSD style"}), NormalizeLicense(License{Name: "MIT License"})})
+	assert.Equal(t, LicenseAllow, action)
+
+	// nor do guesses satisfy the licenses of an exception
+	policy.Default = LicenseDeny
+	policy.Exceptions = []LicenseException{{Artifact: "org.example", Licenses: []string{"MIT"}}}
+	action, _ = policy.decide(node, []LicenseMatch{NormalizeLicense(License{Name: "mit-like"})})
+	assert.Equal(t, LicenseDeny, action)
+}
@@ -0,0 +1,104 @@
+package mvnparse
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeLicense(t *testing.T) {
+	for _, tc := range []struct {
+		license    License
+		id         string
+		confidence LicenseConfidence
+	}{
+		{License{Name: "Apache-2.0"}, "Apache-2.0", LicenseExact},
+		{License{Name: "whatever", URL: "https://www.apache.org/licenses/LICENSE-2.0.txt"}, "Apache-2.0", LicenseExact},
+		{License{URL: "http://www.eclipse.org/legal/epl-v20.html"}, "EPL-2.0", LicenseExact},
+		{License{Name: "The Apache Software License, Version 2.0"}, "Apache-2.0", LicenseHigh},
+		{License{Name: "the apache license version 2.0"}, "Apache-2.0", LicenseHigh},
+		{License{Name: "The MIT License (MIT)"}, "MIT", LicenseHigh},
+		{License{Name: "Eclipse Distribution License - v 1.0"}, "BSD-3-Clause", LicenseHigh},
+		{License{Name: "CDDL + GPLv2 with classpath exception"}, "CDDL-1.1 OR GPL-2.0-only WITH Classpath-exception-2.0", LicenseHigh},
+		{License{Name: "Apache Software Licenses 2"}, "Apache-2.0", LicenseLow},
+		{License{Name: "GNU Lesser Public License 2.1 or later"}, "LGPL-2.1-only", LicenseLow},
+		{License{Name: "BSD style"}, "BSD-3-Clause", LicenseLow},
+		{License{Name: "Proprietary"}, "", LicenseNoMatch},
+		{License{}, "", LicenseNoMatch},
+	} {
+		m := NormalizeLicense(tc.license)
+		assert.Equal(t, tc.id, m.ID, tc.license.Name)
+		assert.Equal(t, tc.confidence, m.Confidence, tc.license.Name)
+		assert.Equal(t, tc.license, m.License)
+	}
+	assert.Equal(t, "high", LicenseHigh.String())
+}
+
+func TestNormalizeLicenses_Inherited(t *testing.T) {
+	child := &Project{}
+	parent := &Project{Licenses: &[]License{{Name: "MIT License"}}}
+	grandParent := &Project{Licenses: &[]License{{Name: "Apache-2.0"}}}
+	matches := NormalizeLicenses([]*Project{child, parent, grandParent})
+	assert.Len(t, matches, 1)
+	assert.Equal(t, "MIT", matches[0].ID)
+	assert.Empty(t, NormalizeLicenses([]*Project{child}))
+}
+
+const testLibParentPom = `<project>
+  <groupId>org.example</groupId>
+  <artifactId>lib-parent</artifactId>
+  <version>3</version>
+  <packaging>pom</packaging>
+  <licenses>
+    <license>
+      <name>Eclipse Public License - v 2.0</name>
+    </license>
+  </licenses>
+</project>`
+
+const testLibPom = `<project>
+  <parent>
+    <groupId>org.example</groupId>
+    <artifactId>lib-parent</artifactId>
+    <version>3</version>
+  </parent>
+  <artifactId>lib</artifactId>
+  <version>1.0</version>
+</project>`
+
+func TestParentChain(t *testing.T) {
+	dir := writeTestReactor(t)
+	defer os.RemoveAll(dir)
+	repo := NewLocalRepository(filepath.Join(dir, "repository"))
+
+	core, err := ParseFile(filepath.Join(dir, "core"))
+	assert.NoError(t, err)
+	chain, err := ParentChain(core, repo)
+	assert.NoError(t, err)
+	assert.Len(t, chain, 2)
+	assert.Equal(t, filepath.Join(dir, "pom.xml"), chain[1].Path)
+
+	// not found on disk nor in the repository
+	core.Project.Parent.Version = "2.0"
+	_, err = ParentChain(core, repo)
+	assert.Error(t, err)
+
+	lib := Coordinates{GroupId: "org.example", ArtifactId: "lib", Version: "1.0"}
+	parent := Coordinates{GroupId: "org.example", ArtifactId: "lib-parent", Version: "3", Type: "pom"}
+	for c, content := range map[Coordinates]string{lib: testLibPom, parent: testLibParentPom} {
+		assert.NoError(t, os.MkdirAll(filepath.Dir(repo.PomPath(c)), 0755))
+		assert.NoError(t, ioutil.WriteFile(repo.PomPath(c), []byte(content), 0644))
+	}
+	core.Project.Parent = &Parent{GroupId: "org.example", ArtifactId: "lib-parent", Version: "3"}
+	chain, err = ParentChain(core, repo)
+	assert.NoError(t, err)
+	assert.Equal(t, repo.PomPath(parent), chain[1].Path)
+
+	node := &DependencyNode{Dependency: lib.ToDependency()}
+	matches := node.licenses(repo)
+	assert.Len(t, matches, 1)
+	assert.Equal(t, "EPL-2.0", matches[0].ID)
+}
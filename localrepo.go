@@ -0,0 +1,78 @@
+package mvnparse
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LocalRepository is a repository laid out like ~/.m2/repository.
+type LocalRepository struct {
+	Dir string
+	// Handlers maps dependency types to files, DefaultArtifactHandlers when nil.
+	Handlers *ArtifactHandlers
+}
+
+func NewLocalRepository(dir string) *LocalRepository {
+	return &LocalRepository{Dir: dir}
+}
+
+// DefaultLocalRepository returns the repository in ~/.m2/repository.
+func DefaultLocalRepository() (*LocalRepository, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	return NewLocalRepository(filepath.Join(home, ".m2", "repository")), nil
+}
+
+func (r *LocalRepository) handlers() *ArtifactHandlers {
+	if r.Handlers == nil {
+		return DefaultArtifactHandlers
+	}
+	return r.Handlers
+}
+
+// Path returns the file of the artifact c. Timestamped snapshots are stored
+// in the directory of their -SNAPSHOT base version.
+func (r *LocalRepository) Path(c Coordinates) string {
+	name := c.ArtifactId + "-" + c.Version
+	if classifier := c.ArtifactClassifierWith(r.handlers()); classifier != "" {
+		name += "-" + classifier
+	}
+	name += "." + c.ExtensionWith(r.handlers())
+	return filepath.Join(r.versionDir(c.GroupId, c.ArtifactId, SnapshotBaseVersion(c.Version)), name)
+}
+
+// PomPath returns the POM file of the artifact c.
+func (r *LocalRepository) PomPath(c Coordinates) string {
+	return r.Path(Coordinates{GroupId: c.GroupId, ArtifactId: c.ArtifactId, Version: c.Version, Type: "pom"})
+}
+
+// MetadataPath returns the maven-metadata-<repositoryId>.xml file at artifact
+// level, or at version level when version is set. An empty repositoryId
+// means the metadata written by local installs.
+func (r *LocalRepository) MetadataPath(groupId, artifactId, version, repositoryId string) string {
+	if repositoryId == "" {
+		repositoryId = "local"
+	}
+	dir := r.versionDir(groupId, artifactId, "")
+	if version != "" {
+		dir = r.versionDir(groupId, artifactId, SnapshotBaseVersion(version))
+	}
+	return filepath.Join(dir, "maven-metadata-"+repositoryId+".xml")
+}
+
+func (r *LocalRepository) versionDir(groupId, artifactId, version string) string {
+	parts := append([]string{r.Dir}, strings.Split(groupId, ".")...)
+	parts = append(parts, artifactId)
+	if version != "" {
+		parts = append(parts, version)
+	}
+	return filepath.Join(parts...)
+}
+
+// Project parses the POM of the artifact c.
+func (r *LocalRepository) Project(c Coordinates) (*Project, error) {
+	return Parse(r.PomPath(c))
+}
@@ -0,0 +1,259 @@
+package mvnparse
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Location is the position of an element in a POM, lines and columns
+// starting at 1.
+type Location struct {
+	File   string
+	Line   int
+	Column int
+}
+
+func (l Location) String() string {
+	if l.File == "" {
+		return fmt.Sprintf("%d:%d", l.Line, l.Column)
+	}
+	return fmt.Sprintf("%s:%d:%d", l.File, l.Line, l.Column)
+}
+
+// Locations maps the elements of a POM, configuration included, to where
+// they start. Elements are addressed by paths such as
+// /project/dependencies/dependency[2]/version, the paths Problem uses, where
+// a missing index stands for [1] and a trailing /@attribute for its element.
+type Locations struct {
+	File  string
+	paths map[string]Location
+	// keys identify the elements of lists, such as dependencies, by their
+	// key rather than their position: keys maps their indexed path to a
+	// segment such as dependency{junit:junit:jar}, keyed maps the paths
+	// made of these segments back to indexed paths.
+	keys  map[string]string
+	keyed map[string]string
+}
+
+// ParseWithLocations parses the POM at path along with the locations of its
+// elements.
+func ParseWithLocations(path string) (*Project, *Locations, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return parseWithLocations(data, path)
+}
+
+func ParseStrWithLocations(xmlStr string) (*Project, *Locations, error) {
+	return parseWithLocations([]byte(xmlStr), "")
+}
+
+func parseWithLocations(data []byte, file string) (*Project, *Locations, error) {
+	var project Project
+	if err := xml.Unmarshal(data, &project); err != nil {
+		return nil, nil, err
+	}
+	locations, err := locate(data, file)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &project, locations, nil
+}
+
+func locate(data []byte, file string) (*Locations, error) {
+	lineStarts := []int{0}
+	for i, b := range data {
+		if b == '\n' {
+			lineStarts = append(lineStarts, i+1)
+		}
+	}
+	position := func(offset int) Location {
+		line := sort.SearchInts(lineStarts, offset+1)
+		start := lineStarts[line-1]
+		return Location{File: file, Line: line, Column: utf8.RuneCount(data[start:offset]) + 1}
+	}
+
+	type frame struct {
+		name   string
+		path   string
+		counts map[string]int
+		text   strings.Builder
+		// fields holds the text of the children of list elements.
+		fields map[string]string
+	}
+	l := &Locations{File: file, paths: map[string]Location{}, keys: map[string]string{}, keyed: map[string]string{}}
+	stack := []*frame{{counts: map[string]int{}}}
+	d := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		offset := int(d.InputOffset())
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			parent := stack[len(stack)-1]
+			parent.counts[t.Name.Local]++
+			path := parent.path + "/" + t.Name.Local + "[" + strconv.Itoa(parent.counts[t.Name.Local]) + "]"
+			l.paths[path] = position(offset)
+			f := &frame{name: t.Name.Local, path: path, counts: map[string]int{}}
+			if _, ok := listElementKey(t.Name.Local, nil); ok {
+				f.fields = map[string]string{}
+			}
+			stack = append(stack, f)
+		case xml.CharData:
+			stack[len(stack)-1].text.Write(t)
+		case xml.EndElement:
+			f := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if parent := stack[len(stack)-1]; parent.fields != nil {
+				parent.fields[f.name] = strings.TrimSpace(f.text.String())
+			}
+			if f.fields != nil {
+				key, _ := listElementKey(f.name, f.fields)
+				l.keys[f.path] = f.name + "{" + key + "}"
+			}
+		}
+	}
+	for path := range l.paths {
+		keyed, _ := l.keyedPath(path)
+		l.keyed[keyed] = path
+	}
+	return l, nil
+}
+
+// listElementKey returns the key identifying the element name of a list
+// from the text of its children, the way Maven merges lists when
+// inheriting, and whether name is such an element.
+func listElementKey(name string, fields map[string]string) (string, bool) {
+	switch name {
+	case "dependency":
+		key := fields["groupId"] + ":" + fields["artifactId"] + ":"
+		if fields["type"] == "" {
+			key += "jar"
+		} else {
+			key += fields["type"]
+		}
+		if fields["classifier"] != "" {
+			key += ":" + fields["classifier"]
+		}
+		return key, true
+	case "plugin":
+		groupId := fields["groupId"]
+		if groupId == "" {
+			groupId = defaultPluginGroupId
+		}
+		return groupId + ":" + fields["artifactId"], true
+	case "extension", "exclusion":
+		return fields["groupId"] + ":" + fields["artifactId"], true
+	case "execution":
+		if fields["id"] == "" {
+			return "default", true
+		}
+		return fields["id"], true
+	case "profile", "repository", "pluginRepository":
+		return fields["id"], true
+	}
+	return "", false
+}
+
+// keyedPath replaces the list elements of path by their key. It fails when
+// a list element of path is not in the POM or path is malformed.
+func (l *Locations) keyedPath(path string) (string, bool) {
+	var keys map[string]string
+	if l != nil {
+		keys = l.keys
+	}
+	var indexed, keyed strings.Builder
+	for _, segment := range strings.Split(strings.TrimPrefix(normalizeLocationPath(path), "/"), "/") {
+		indexed.WriteString("/" + segment)
+		if key, ok := keys[indexed.String()]; ok {
+			keyed.WriteString("/" + key)
+			continue
+		}
+		i := strings.Index(segment, "[")
+		if i < 0 {
+			return "", false
+		}
+		if _, ok := listElementKey(segment[:i], nil); ok {
+			return "", false
+		}
+		keyed.WriteString("/" + segment)
+	}
+	return keyed.String(), true
+}
+
+// normalizeLocationPath indexes every segment of path and drops attributes.
+func normalizeLocationPath(path string) string {
+	var buf strings.Builder
+	for _, segment := range strings.Split(strings.Trim(path, "/"), "/") {
+		if segment == "" || strings.HasPrefix(segment, "@") {
+			continue
+		}
+		buf.WriteByte('/')
+		buf.WriteString(segment)
+		if !strings.HasSuffix(segment, "]") {
+			buf.WriteString("[1]")
+		}
+	}
+	return buf.String()
+}
+
+// Lookup returns the location of the element at path.
+func (l *Locations) Lookup(path string) (Location, bool) {
+	if l == nil {
+		return Location{}, false
+	}
+	loc, ok := l.paths[normalizeLocationPath(path)]
+	return loc, ok
+}
+
+// Nearest returns the location of the element at path or, when it does not
+// exist, such as a missing version, of its closest existing ancestor.
+func (l *Locations) Nearest(path string) (Location, bool) {
+	path = normalizeLocationPath(path)
+	for path != "" {
+		if loc, ok := l.Lookup(path); ok {
+			return loc, true
+		}
+		path = path[:strings.LastIndex(path, "/")]
+	}
+	return Location{}, false
+}
+
+// LookupInChain returns the location of the element at path in the nearest
+// POM of chain declaring it, which tells where an inherited value comes
+// from. Path addresses the first POM as written; the elements of lists,
+// such as dependencies, plugins or profiles, are found in the other POMs by
+// their key, groupId:artifactId[:type[:classifier]] or id, as Maven merges
+// them, rather than by position. A path going through a list element the
+// first POM does not declare matches nothing.
+func LookupInChain(chain []*ProjectFile, path string) (Location, bool) {
+	if len(chain) == 0 {
+		return Location{}, false
+	}
+	keyed, ok := chain[0].Locations.keyedPath(path)
+	if !ok {
+		return Location{}, false
+	}
+	for _, pf := range chain {
+		if pf.Locations == nil {
+			continue
+		}
+		if indexed, ok := pf.Locations.keyed[keyed]; ok {
+			return pf.Locations.paths[indexed], true
+		}
+	}
+	return Location{}, false
+}
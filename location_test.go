@@ -0,0 +1,131 @@
+package mvnparse
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseStrWithLocations(t *testing.T) {
+	p, locations, err := ParseStrWithLocations(`<?xml version="1.0"?>
+<project>
+  <artifactId>app</artifactId>
+  <dependencies>
+    <dependency><groupId>junit</groupId><artifactId>junit</artifactId></dependency>
+    <dependency>
+      <groupId>org.slf4j</groupId>
+    </dependency>
+  </dependencies>
+  <build><plugins><plugin><configuration><compilerArgs><arg>-Xlint</arg><arg x="1">-Werror</arg></compilerArgs></configuration></plugin></plugins></build>
+</project>`)
+	assert.NoError(t, err)
+	assert.Equal(t, "app", p.ArtifactId)
+
+	for path, want := range map[string]string{
+		"/project":                                                           "2:1",
+		"/project/artifactId":                                                "3:3",
+		"/project[1]/dependencies/dependency[1]":                             "5:5",
+		"/project/dependencies/dependency/artifactId":                        "5:41",
+		"/project/dependencies/dependency[2]/groupId":                        "7:7",
+		"/project/build/plugins/plugin/configuration/compilerArgs/arg[2]/@x": "10:73",
+	} {
+		loc, ok := locations.Lookup(path)
+		assert.True(t, ok, path)
+		assert.Equal(t, want, loc.String(), path)
+	}
+	_, ok := locations.Lookup("/project/dependencies/dependency[2]/version")
+	assert.False(t, ok)
+	loc, ok := locations.Nearest("/project/dependencies/dependency[2]/version")
+	assert.True(t, ok)
+	assert.Equal(t, "6:5", loc.String())
+
+	_, _, err = ParseStrWithLocations("<project>")
+	assert.Error(t, err)
+}
+
+func TestLookupInChain(t *testing.T) {
+	dir := writeTestReactor(t)
+	defer os.RemoveAll(dir)
+	core, err := ParseFile(filepath.Join(dir, "core"))
+	assert.NoError(t, err)
+	chain, err := ParentChain(core, nil)
+	assert.NoError(t, err)
+
+	loc, ok := LookupInChain(chain, "/project/properties/jackson.version")
+	assert.True(t, ok)
+	assert.Equal(t, Location{File: filepath.Join(dir, "pom.xml"), Line: 15, Column: 5}, loc)
+	loc, ok = LookupInChain(chain, "/project/artifactId")
+	assert.True(t, ok)
+	assert.Equal(t, core.Path, loc.File)
+
+	// problems point at their element
+	core.Project.Dependencies = nil
+	core.Project.ModelVersion = "4.1.0"
+	problems := core.Validate()
+	assert.Len(t, problems, 1)
+	assert.Equal(t, &Location{File: core.Path, Line: 2, Column: 3}, problems[0].Location)
+	assert.Equal(t, "[ERROR] 'modelVersion' must be one of [4.0.0] but is '4.1.0'. @ "+core.Path+":2:3", problems[0].String())
+}
+
+func TestLookupInChain_Lists(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{
+		"pom.xml": `<project>
+  <groupId>com.example</groupId>
+  <artifactId>parent</artifactId>
+  <version>1.0</version>
+  <build>
+    <plugins>
+      <plugin>
+        <artifactId>maven-surefire-plugin</artifactId>
+        <version>3.2.2</version>
+      </plugin>
+      <plugin>
+        <artifactId>maven-compiler-plugin</artifactId>
+        <version>3.12.1</version>
+      </plugin>
+    </plugins>
+  </build>
+</project>`,
+		"child/pom.xml": `<project>
+  <parent>
+    <groupId>com.example</groupId>
+    <artifactId>parent</artifactId>
+    <version>1.0</version>
+  </parent>
+  <artifactId>child</artifactId>
+  <build>
+    <plugins>
+      <plugin>
+        <groupId>org.apache.maven.plugins</groupId>
+        <artifactId>maven-compiler-plugin</artifactId>
+      </plugin>
+    </plugins>
+  </build>
+</project>`,
+	})
+	defer os.RemoveAll(dir)
+	child, err := ParseFile(filepath.Join(dir, "child"))
+	assert.NoError(t, err)
+	chain, err := ParentChain(child, nil)
+	assert.NoError(t, err)
+
+	// the compiler plugin is the second of the parent, not the first
+	loc, ok := LookupInChain(chain, "/project/build/plugins/plugin[1]/version")
+	assert.True(t, ok)
+	assert.Equal(t, Location{File: filepath.Join(dir, "pom.xml"), Line: 13, Column: 9}, loc)
+	loc, ok = LookupInChain(chain, "/project/build/plugins/plugin/artifactId")
+	assert.True(t, ok)
+	assert.Equal(t, child.Path, loc.File)
+
+	// the child declares no second plugin to match
+	_, ok = LookupInChain(chain, "/project/build/plugins/plugin[2]/version")
+	assert.False(t, ok)
+
+	// malformed paths are not found
+	for _, path := range []string{"/project/version]", "", "/project/build/plugins/plugin[1/version"} {
+		_, ok = LookupInChain(chain, path)
+		assert.False(t, ok, path)
+	}
+}
@@ -0,0 +1,202 @@
+package mvnparse
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const snapshotSuffix = "-SNAPSHOT"
+
+var (
+	ErrSnapshotsDisabled = errors.New("snapshots are disabled for this repository")
+
+	timestampedSnapshot = regexp.MustCompile(`^(.*)-([0-9]{8}\.[0-9]{6})-([0-9]+)$`)
+)
+
+// Metadata is the content of a maven-metadata.xml file, at group,
+// artifact or version level.
+type Metadata struct {
+	XMLName      xml.Name          `xml:"metadata"`
+	ModelVersion string            `xml:"modelVersion,attr,omitempty"`
+	GroupId      string            `xml:"groupId,omitempty"`
+	ArtifactId   string            `xml:"artifactId,omitempty"`
+	Version      string            `xml:"version,omitempty"`
+	Versioning   *Versioning       `xml:"versioning,omitempty"`
+	Plugins      *[]MetadataPlugin `xml:"plugins>plugin,omitempty"`
+}
+
+type Versioning struct {
+	Latest           string             `xml:"latest,omitempty"`
+	Release          string             `xml:"release,omitempty"`
+	Versions         *[]string          `xml:"versions>version,omitempty"`
+	LastUpdated      string             `xml:"lastUpdated,omitempty"`
+	Snapshot         *Snapshot          `xml:"snapshot,omitempty"`
+	SnapshotVersions *[]SnapshotVersion `xml:"snapshotVersions>snapshotVersion,omitempty"`
+}
+
+type Snapshot struct {
+	Timestamp   string `xml:"timestamp,omitempty"`
+	BuildNumber int    `xml:"buildNumber,omitempty"`
+	LocalCopy   bool   `xml:"localCopy,omitempty"`
+}
+
+type SnapshotVersion struct {
+	Classifier string `xml:"classifier,omitempty"`
+	Extension  string `xml:"extension,omitempty"`
+	Value      string `xml:"value,omitempty"`
+	Updated    string `xml:"updated,omitempty"`
+}
+
+type MetadataPlugin struct {
+	Name       string `xml:"name,omitempty"`
+	Prefix     string `xml:"prefix,omitempty"`
+	ArtifactId string `xml:"artifactId,omitempty"`
+}
+
+func ParseMetadataStr(xmlStr string) (*Metadata, error) {
+	var metadata Metadata
+	err := xml.Unmarshal([]byte(xmlStr), &metadata)
+	if err != nil {
+		return nil, err
+	}
+	return &metadata, nil
+}
+
+func ParseMetadata(path string) (*Metadata, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseMetadataStr(string(b))
+}
+
+// IsEnabled reports whether the policy allows artifacts from the
+// repository; a missing policy or an empty <enabled> means enabled.
+func (p *RepositoryPolicy) IsEnabled() bool {
+	if p == nil {
+		return true
+	}
+	return strings.TrimSpace(p.Enabled) != "false"
+}
+
+// UpdateRequired reports whether metadata last fetched at lastUpdated must be
+// refreshed at now, following the updatePolicy values always, daily (the
+// default), interval:minutes and never.
+func (p *RepositoryPolicy) UpdateRequired(lastUpdated, now time.Time) bool {
+	if lastUpdated.IsZero() {
+		return true
+	}
+	policy := ""
+	if p != nil {
+		policy = strings.TrimSpace(p.UpdatePolicy)
+	}
+	switch {
+	case policy == "always":
+		return true
+	case policy == "never":
+		return false
+	case strings.HasPrefix(policy, "interval:"):
+		minutes, err := strconv.Atoi(strings.TrimPrefix(policy, "interval:"))
+		if err != nil {
+			return true
+		}
+		return !now.Before(lastUpdated.Add(time.Duration(minutes) * time.Minute))
+	default:
+		y1, m1, d1 := lastUpdated.Date()
+		y2, m2, d2 := now.Date()
+		return y1 != y2 || m1 != m2 || d1 != d2
+	}
+}
+
+// IsSnapshot reports whether version is a snapshot, either in its base form
+// (1.0-SNAPSHOT) or timestamped (1.0-20261018.101010-3).
+func IsSnapshot(version string) bool {
+	return strings.HasSuffix(version, snapshotSuffix) || timestampedSnapshot.MatchString(version)
+}
+
+// TimestampedSnapshot is a concrete snapshot build deployed to a remote
+// repository.
+type TimestampedSnapshot struct {
+	BaseVersion string
+	Timestamp   string
+	BuildNumber int
+}
+
+// ParseTimestampedSnapshot splits a version such as 1.0-20261018.101010-3
+// into its base version (1.0-SNAPSHOT), timestamp and build number.
+func ParseTimestampedSnapshot(version string) (TimestampedSnapshot, bool) {
+	m := timestampedSnapshot.FindStringSubmatch(version)
+	if m == nil {
+		return TimestampedSnapshot{}, false
+	}
+	buildNumber, err := strconv.Atoi(m[3])
+	if err != nil {
+		return TimestampedSnapshot{}, false
+	}
+	return TimestampedSnapshot{
+		BaseVersion: m[1] + snapshotSuffix,
+		Timestamp:   m[2],
+		BuildNumber: buildNumber,
+	}, true
+}
+
+func (s TimestampedSnapshot) String() string {
+	return fmt.Sprintf("%s-%s-%d", strings.TrimSuffix(s.BaseVersion, snapshotSuffix), s.Timestamp, s.BuildNumber)
+}
+
+// SnapshotBaseVersion maps a timestamped snapshot version back to the
+// -SNAPSHOT version it was deployed as. Other versions are returned as is.
+func SnapshotBaseVersion(version string) string {
+	if s, ok := ParseTimestampedSnapshot(version); ok {
+		return s.BaseVersion
+	}
+	return version
+}
+
+// ResolveSnapshotVersion resolves the -SNAPSHOT version of d to the
+// timestamped version recorded in the version level metadata m, honoring the
+// snapshots policy of the repository the metadata came from. Release and
+// already timestamped versions are returned unchanged. Entries are matched
+// on the file the dependency type maps to, so a test-jar is the jar with the
+// tests classifier.
+func ResolveSnapshotVersion(d Dependency, m *Metadata, snapshots *RepositoryPolicy) (string, error) {
+	if !strings.HasSuffix(d.Version, snapshotSuffix) {
+		return d.Version, nil
+	}
+	if !snapshots.IsEnabled() {
+		return "", ErrSnapshotsDisabled
+	}
+	if m == nil || m.Versioning == nil {
+		return "", fmt.Errorf("no snapshot metadata for %s:%s:%s", d.GroupId, d.ArtifactId, d.Version)
+	}
+	if m.Version != "" && m.Version != d.Version {
+		return "", fmt.Errorf("metadata is for version %s, not %s", m.Version, d.Version)
+	}
+	versioning := m.Versioning
+	if versioning.Snapshot != nil && versioning.Snapshot.LocalCopy {
+		return d.Version, nil
+	}
+
+	c := d.Coordinates()
+	if versioning.SnapshotVersions != nil {
+		for _, sv := range *versioning.SnapshotVersions {
+			if sv.Classifier == c.ArtifactClassifier() && sv.Extension == c.Extension() {
+				return sv.Value, nil
+			}
+		}
+	}
+	if versioning.Snapshot != nil && versioning.Snapshot.Timestamp != "" {
+		return TimestampedSnapshot{
+			BaseVersion: d.Version,
+			Timestamp:   versioning.Snapshot.Timestamp,
+			BuildNumber: versioning.Snapshot.BuildNumber,
+		}.String(), nil
+	}
+	return d.Version, nil
+}
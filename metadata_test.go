@@ -0,0 +1,144 @@
+package mvnparse
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const snapshotMetadata = `<?xml version="1.0" encoding="UTF-8"?>
+<metadata modelVersion="1.1.0">
+  <groupId>com.example</groupId>
+  <artifactId>lib</artifactId>
+  <version>1.0-SNAPSHOT</version>
+  <versioning>
+    <snapshot>
+      <timestamp>20261018.101010</timestamp>
+      <buildNumber>3</buildNumber>
+    </snapshot>
+    <lastUpdated>20261018101010</lastUpdated>
+    <snapshotVersions>
+      <snapshotVersion>
+        <extension>jar</extension>
+        <value>1.0-20261018.101010-3</value>
+        <updated>20261018101010</updated>
+      </snapshotVersion>
+      <snapshotVersion>
+        <classifier>sources</classifier>
+        <extension>jar</extension>
+        <value>1.0-20261017.090000-2</value>
+        <updated>20261017090000</updated>
+      </snapshotVersion>
+    </snapshotVersions>
+  </versioning>
+</metadata>`
+
+func TestParseMetadataStr(t *testing.T) {
+	m, err := ParseMetadataStr(snapshotMetadata)
+	assert.NoError(t, err)
+	assert.Equal(t, "1.1.0", m.ModelVersion)
+	assert.Equal(t, "lib", m.ArtifactId)
+	assert.Equal(t, 3, m.Versioning.Snapshot.BuildNumber)
+	assert.Len(t, *m.Versioning.SnapshotVersions, 2)
+}
+
+func TestResolveSnapshotVersion(t *testing.T) {
+	m, err := ParseMetadataStr(snapshotMetadata)
+	assert.NoError(t, err)
+
+	d := Dependency{GroupId: "com.example", ArtifactId: "lib", Version: "1.0-SNAPSHOT"}
+	v, err := ResolveSnapshotVersion(d, m, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "1.0-20261018.101010-3", v)
+
+	d.Classifier = "sources"
+	v, err = ResolveSnapshotVersion(d, m, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "1.0-20261017.090000-2", v)
+
+	// no snapshotVersion entry, falls back to the snapshot timestamp
+	d.Classifier = "javadoc"
+	v, err = ResolveSnapshotVersion(d, m, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "1.0-20261018.101010-3", v)
+
+	_, err = ResolveSnapshotVersion(d, m, &RepositoryPolicy{Enabled: "false"})
+	assert.Equal(t, ErrSnapshotsDisabled, err)
+
+	d.Version = "1.0"
+	v, err = ResolveSnapshotVersion(d, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "1.0", v)
+}
+
+func TestResolveSnapshotVersion_Types(t *testing.T) {
+	m, err := ParseMetadataStr(`<metadata>
+  <version>1.0-SNAPSHOT</version>
+  <versioning>
+    <snapshot>
+      <timestamp>20261018.101010</timestamp>
+      <buildNumber>3</buildNumber>
+    </snapshot>
+    <snapshotVersions>
+      <snapshotVersion><extension>jar</extension><value>1.0-20261018.101010-3</value></snapshotVersion>
+      <snapshotVersion><classifier>tests</classifier><extension>jar</extension><value>1.0-20261018.101010-2</value></snapshotVersion>
+      <snapshotVersion><extension>pom</extension><value>1.0-20261018.101010-1</value></snapshotVersion>
+      <snapshotVersion><classifier>client</classifier><extension>jar</extension><value>1.0-20261017.090000-4</value></snapshotVersion>
+    </snapshotVersions>
+  </versioning>
+</metadata>`)
+	assert.NoError(t, err)
+
+	for typ, expected := range map[string]string{
+		"":             "1.0-20261018.101010-3",
+		"jar":          "1.0-20261018.101010-3",
+		"maven-plugin": "1.0-20261018.101010-3",
+		"test-jar":     "1.0-20261018.101010-2",
+		"pom":          "1.0-20261018.101010-1",
+		"ejb-client":   "1.0-20261017.090000-4",
+		// no war entry, falls back to the snapshot timestamp
+		"war": "1.0-20261018.101010-3",
+	} {
+		d := Dependency{GroupId: "com.example", ArtifactId: "lib", Version: "1.0-SNAPSHOT", Type: typ}
+		v, err := ResolveSnapshotVersion(d, m, nil)
+		assert.NoError(t, err, typ)
+		assert.Equal(t, expected, v, typ)
+	}
+
+	// an explicit classifier wins over the one implied by the type
+	d := Dependency{GroupId: "com.example", ArtifactId: "lib", Version: "1.0-SNAPSHOT", Type: "test-jar", Classifier: "client"}
+	v, err := ResolveSnapshotVersion(d, m, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "1.0-20261017.090000-4", v)
+}
+
+func TestParseTimestampedSnapshot(t *testing.T) {
+	s, ok := ParseTimestampedSnapshot("1.0-20261018.101010-3")
+	assert.True(t, ok)
+	assert.Equal(t, "1.0-SNAPSHOT", s.BaseVersion)
+	assert.Equal(t, "20261018.101010", s.Timestamp)
+	assert.Equal(t, 3, s.BuildNumber)
+	assert.Equal(t, "1.0-20261018.101010-3", s.String())
+
+	_, ok = ParseTimestampedSnapshot("1.0-SNAPSHOT")
+	assert.False(t, ok)
+	assert.Equal(t, "2.1-SNAPSHOT", SnapshotBaseVersion("2.1-20261018.101010-12"))
+	assert.Equal(t, "2.1", SnapshotBaseVersion("2.1"))
+	assert.True(t, IsSnapshot("2.1-20261018.101010-12"))
+	assert.False(t, IsSnapshot("2.1"))
+}
+
+func TestRepositoryPolicy_UpdateRequired(t *testing.T) {
+	last := time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC)
+	var daily *RepositoryPolicy
+	assert.False(t, daily.UpdateRequired(last, last.Add(time.Hour)))
+	assert.True(t, daily.UpdateRequired(last, last.Add(24*time.Hour)))
+
+	interval := &RepositoryPolicy{UpdatePolicy: "interval:30"}
+	assert.False(t, interval.UpdateRequired(last, last.Add(10*time.Minute)))
+	assert.True(t, interval.UpdateRequired(last, last.Add(30*time.Minute)))
+
+	assert.False(t, (&RepositoryPolicy{UpdatePolicy: "never"}).UpdateRequired(last, last.Add(1000*time.Hour)))
+	assert.True(t, (&RepositoryPolicy{UpdatePolicy: "always"}).UpdateRequired(last, last))
+}
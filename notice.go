@@ -0,0 +1,201 @@
+package mvnparse
+
+import (
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+type NoticeFormat string
+
+const (
+	NoticeMarkdown NoticeFormat = "markdown"
+	NoticeText     NoticeFormat = "text"
+	NoticeHTML     NoticeFormat = "html"
+)
+
+const unknownLicense = "Unknown license"
+
+// NoticeReport is the third-party attribution of a project, grouped by
+// license.
+type NoticeReport struct {
+	Project  string
+	Licenses []NoticeLicense
+}
+
+type NoticeLicense struct {
+	// Name is the SPDX identifier when the license is recognized.
+	Name      string
+	URLs      []string
+	Artifacts []NoticeArtifact
+}
+
+type NoticeArtifact struct {
+	Name            string
+	GroupId         string
+	ArtifactId      string
+	Version         string
+	URL             string
+	Organization    string
+	OrganizationURL string
+}
+
+// NewNoticeReport collects the attribution of every artifact of the graph
+// rooted at n, skipping ignoreScopes (typically test and provided, which are
+// not distributed). POMs missing from the nodes are read from repo.
+func NewNoticeReport(n *DependencyNode, repo *LocalRepository, ignoreScopes []string) *NoticeReport {
+	report := &NoticeReport{Project: n.Coordinates().String()}
+	if p := n.loadProject(repo); p != nil && p.Name != "" {
+		report.Project = p.Name
+	}
+	groups := map[string]*NoticeLicense{}
+	// members holds the artifacts of each group, as two licenses of a POM
+	// may normalize to the same one
+	members := map[string]map[string]bool{}
+	seen := map[string]bool{}
+	n.Walk(func(node *DependencyNode, path []*DependencyNode) bool {
+		if !node.Included() {
+			return false
+		}
+		if len(path) == 1 {
+			return true
+		}
+		if containsString(ignoreScopes, node.Scope()) {
+			return false
+		}
+		key := node.Coordinates().String()
+		if seen[key] {
+			return true
+		}
+		seen[key] = true
+
+		chain := node.projectChain(repo)
+		artifact := noticeArtifact(node, chain)
+		licenses := NormalizeLicenses(chain)
+		if len(licenses) == 0 {
+			licenses = []LicenseMatch{{}}
+		}
+		for _, m := range licenses {
+			name := m.ID
+			if m.Confidence < LicenseHigh || name == "" {
+				name = licenseLabel(m.License)
+			}
+			if name == "" {
+				name = unknownLicense
+			}
+			group, ok := groups[name]
+			if !ok {
+				group = &NoticeLicense{Name: name}
+				groups[name] = group
+				members[name] = map[string]bool{}
+			}
+			if m.License.URL != "" && !containsString(group.URLs, m.License.URL) {
+				group.URLs = append(group.URLs, m.License.URL)
+			}
+			if !members[name][key] {
+				members[name][key] = true
+				group.Artifacts = append(group.Artifacts, artifact)
+			}
+		}
+		return true
+	})
+
+	for _, group := range groups {
+		sort.SliceStable(group.Artifacts, func(i, j int) bool {
+			a, b := group.Artifacts[i], group.Artifacts[j]
+			if x, y := strings.ToLower(a.Name), strings.ToLower(b.Name); x != y {
+				return x < y
+			}
+			return a.coordinates() < b.coordinates()
+		})
+		report.Licenses = append(report.Licenses, *group)
+	}
+	sort.SliceStable(report.Licenses, func(i, j int) bool {
+		a, b := report.Licenses[i].Name, report.Licenses[j].Name
+		if (a == unknownLicense) != (b == unknownLicense) {
+			return b == unknownLicense
+		}
+		if x, y := strings.ToLower(a), strings.ToLower(b); x != y {
+			return x < y
+		}
+		return a < b
+	})
+	return report
+}
+
+// coordinates orders artifacts of the same name.
+func (a NoticeArtifact) coordinates() string {
+	return a.GroupId + ":" + a.ArtifactId + ":" + a.Version
+}
+
+func noticeArtifact(node *DependencyNode, chain []*Project) NoticeArtifact {
+	d := node.Dependency
+	a := NoticeArtifact{Name: d.ArtifactId, GroupId: d.GroupId, ArtifactId: d.ArtifactId, Version: d.Version}
+	if len(chain) == 0 {
+		return a
+	}
+	if chain[0].Name != "" {
+		a.Name = chain[0].Name
+	}
+	a.URL = chain[0].URL
+	// the organization is inherited from the closest ancestor declaring one
+	for _, p := range chain {
+		if p.Organization != nil {
+			a.Organization = p.Organization.Name
+			a.OrganizationURL = p.Organization.URL
+			break
+		}
+	}
+	return a
+}
+
+var noticeTextTemplate = template.Must(template.New("text").Parse(
+	`Third-party software included in {{.Project}}
+{{range .Licenses}}
+{{.Name}}{{range .URLs}}
+  {{.}}{{end}}
+{{range .Artifacts}}
+  * {{.Name}} ({{.GroupId}}:{{.ArtifactId}}:{{.Version}}){{if .URL}} - {{.URL}}{{end}}{{if .Organization}}
+    by {{.Organization}}{{if .OrganizationURL}} ({{.OrganizationURL}}){{end}}{{end}}{{end}}
+{{end}}`))
+
+var noticeMarkdownTemplate = template.Must(template.New("markdown").Parse(
+	`# Third-party software included in {{.Project}}
+{{range .Licenses}}
+## {{.Name}}
+{{range .URLs}}
+<{{.}}>
+{{end}}
+{{range .Artifacts}}* {{if .URL}}[{{.Name}}]({{.URL}}){{else}}{{.Name}}{{end}} ` + "`{{.GroupId}}:{{.ArtifactId}}:{{.Version}}`" + `{{if .Organization}} by {{if .OrganizationURL}}[{{.Organization}}]({{.OrganizationURL}}){{else}}{{.Organization}}{{end}}{{end}}
+{{end}}{{end}}`))
+
+var noticeHTMLTemplate = htmltemplate.Must(htmltemplate.New("html").Parse(
+	`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Third-party software included in {{.Project}}</title></head>
+<body>
+<h1>Third-party software included in {{.Project}}</h1>
+{{range .Licenses}}<h2>{{.Name}}</h2>
+{{range .URLs}}<p><a href="{{.}}">{{.}}</a></p>
+{{end}}<ul>
+{{range .Artifacts}}<li>{{if .URL}}<a href="{{.URL}}">{{.Name}}</a>{{else}}{{.Name}}{{end}} <code>{{.GroupId}}:{{.ArtifactId}}:{{.Version}}</code>{{if .Organization}} by {{if .OrganizationURL}}<a href="{{.OrganizationURL}}">{{.Organization}}</a>{{else}}{{.Organization}}{{end}}{{end}}</li>
+{{end}}</ul>
+{{end}}</body>
+</html>
+`))
+
+func (r *NoticeReport) Write(w io.Writer, format NoticeFormat) error {
+	switch format {
+	case NoticeText:
+		return noticeTextTemplate.Execute(w, r)
+	case NoticeMarkdown:
+		return noticeMarkdownTemplate.Execute(w, r)
+	case NoticeHTML:
+		return noticeHTMLTemplate.Execute(w, r)
+	default:
+		return fmt.Errorf("unknown notice format %q", format)
+	}
+}
@@ -0,0 +1,108 @@
+package mvnparse
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func noticeTestGraph() *DependencyNode {
+	root := licensedTestGraph()
+	root.Project = &Project{Name: "Example App"}
+	root.Children[0].Children[0].Project = &Project{
+		Name:         "Guava",
+		URL:          "https://github.com/google/guava",
+		Organization: &Organization{Name: "Google", URL: "https://google.com"},
+		Licenses:     &[]License{{Name: "Apache License 2.0", URL: "https://www.apache.org/licenses/LICENSE-2.0"}},
+	}
+	return root
+}
+
+func TestNewNoticeReport(t *testing.T) {
+	report := NewNoticeReport(noticeTestGraph(), nil, []string{ScopeTest})
+	assert.Equal(t, "Example App", report.Project)
+	var names []string
+	for _, l := range report.Licenses {
+		names = append(names, l.Name)
+	}
+	assert.Equal(t, []string{"Acme Proprietary", "Apache-2.0", "GPL-3.0-only", "LGPL-3.0-only", "MIT"}, names)
+	apache := report.Licenses[1]
+	assert.Equal(t, []string{"https://www.apache.org/licenses/LICENSE-2.0"}, apache.URLs)
+	assert.Equal(t, NoticeArtifact{
+		Name:            "Guava",
+		GroupId:         "com.google.guava",
+		ArtifactId:      "guava",
+		Version:         "31.1-jre",
+		URL:             "https://github.com/google/guava",
+		Organization:    "Google",
+		OrganizationURL: "https://google.com",
+	}, apache.Artifacts[0])
+
+	root := testGraph()
+	report = NewNoticeReport(root, nil, nil)
+	assert.Len(t, report.Licenses, 1)
+	assert.Equal(t, unknownLicense, report.Licenses[0].Name)
+	assert.Len(t, report.Licenses[0].Artifacts, 6)
+}
+
+func TestNewNoticeReport_Duplicates(t *testing.T) {
+	root := NewProjectNode(&Project{GroupId: "com.example", ArtifactId: "app", Version: "1.0"})
+	for _, d := range []Dependency{
+		{GroupId: "org.b", ArtifactId: "util", Version: "1.0"},
+		{GroupId: "org.a", ArtifactId: "util", Version: "2.0"},
+		{GroupId: "org.a", ArtifactId: "util", Version: "1.0"},
+	} {
+		root.Children = append(root.Children, &DependencyNode{Dependency: d, Project: &Project{
+			Name: "Util",
+			// both normalize to Apache-2.0
+			Licenses: &[]License{{Name: "Apache License 2.0"}, {Name: "The Apache Software License, Version 2.0"}},
+		}})
+	}
+	report := NewNoticeReport(root, nil, nil)
+	assert.Len(t, report.Licenses, 1)
+	var coordinates []string
+	for _, a := range report.Licenses[0].Artifacts {
+		coordinates = append(coordinates, a.coordinates())
+	}
+	// one entry per artifact, ordered by coordinates within a name
+	assert.Equal(t, []string{"org.a:util:1.0", "org.a:util:2.0", "org.b:util:1.0"}, coordinates)
+}
+
+func TestNoticeReport_Write(t *testing.T) {
+	root := noticeTestGraph()
+	root.Children = root.Children[:1]
+	report := NewNoticeReport(root, nil, nil)
+
+	var buf bytes.Buffer
+	assert.NoError(t, report.Write(&buf, NoticeText))
+	assert.Equal(t, `Third-party software included in Example App
+
+Acme Proprietary
+
+  * lib-a (com.example:lib-a:1.0)
+
+Apache-2.0
+  https://www.apache.org/licenses/LICENSE-2.0
+
+  * Guava (com.google.guava:guava:31.1-jre) - https://github.com/google/guava
+    by Google (https://google.com)
+
+MIT
+
+  * slf4j-api (org.slf4j:slf4j-api:2.0.9)
+`, buf.String())
+
+	buf.Reset()
+	assert.NoError(t, report.Write(&buf, NoticeMarkdown))
+	assert.Contains(t, buf.String(), "# Third-party software included in Example App\n")
+	assert.Contains(t, buf.String(), "## Apache-2.0\n\n<https://www.apache.org/licenses/LICENSE-2.0>\n\n* [Guava](https://github.com/google/guava) `com.google.guava:guava:31.1-jre` by [Google](https://google.com)\n")
+
+	buf.Reset()
+	report.Licenses[0].Name = "Acme <Proprietary>"
+	assert.NoError(t, report.Write(&buf, NoticeHTML))
+	assert.Contains(t, buf.String(), "<h2>Acme &lt;Proprietary&gt;</h2>")
+	assert.Contains(t, buf.String(), `<li><a href="https://github.com/google/guava">Guava</a> <code>com.google.guava:guava:31.1-jre</code> by <a href="https://google.com">Google</a></li>`)
+
+	assert.Error(t, report.Write(&buf, "pdf"))
+}
@@ -0,0 +1,330 @@
+package mvnparse
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const osvEcosystem = "Maven"
+
+// OSVAdvisory is a vulnerability in the OSV format, as found in the
+// ecosystem exports at https://osv-vulnerabilities.storage.googleapis.com.
+type OSVAdvisory struct {
+	ID        string        `json:"id"`
+	Aliases   []string      `json:"aliases,omitempty"`
+	Summary   string        `json:"summary,omitempty"`
+	Details   string        `json:"details,omitempty"`
+	Modified  string        `json:"modified,omitempty"`
+	Published string        `json:"published,omitempty"`
+	Withdrawn string        `json:"withdrawn,omitempty"`
+	Severity  []OSVSeverity `json:"severity,omitempty"`
+	Affected  []OSVAffected `json:"affected,omitempty"`
+}
+
+type OSVSeverity struct {
+	Type  string `json:"type"`
+	Score string `json:"score"`
+}
+
+type OSVAffected struct {
+	Package  OSVPackage `json:"package"`
+	Ranges   []OSVRange `json:"ranges,omitempty"`
+	Versions []string   `json:"versions,omitempty"`
+}
+
+type OSVPackage struct {
+	Ecosystem string `json:"ecosystem"`
+	// Name is groupId:artifactId for Maven.
+	Name string `json:"name"`
+	Purl string `json:"purl,omitempty"`
+}
+
+type OSVRange struct {
+	Type   string     `json:"type"`
+	Repo   string     `json:"repo,omitempty"`
+	Events []OSVEvent `json:"events"`
+}
+
+type OSVEvent struct {
+	Introduced   string `json:"introduced,omitempty"`
+	Fixed        string `json:"fixed,omitempty"`
+	LastAffected string `json:"last_affected,omitempty"`
+	Limit        string `json:"limit,omitempty"`
+}
+
+// OSVDatabase indexes Maven advisories by groupId:artifactId.
+type OSVDatabase struct {
+	advisories map[string][]*OSVAdvisory
+}
+
+func NewOSVDatabase() *OSVDatabase {
+	return &OSVDatabase{advisories: map[string][]*OSVAdvisory{}}
+}
+
+// LoadOSVDatabase reads the advisories of a Maven ecosystem export, either
+// the zip archive as downloaded or a directory of extracted JSON files.
+func LoadOSVDatabase(path string) (*OSVDatabase, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	db := NewOSVDatabase()
+	if info.IsDir() {
+		err = filepath.Walk(path, func(p string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() || filepath.Ext(p) != ".json" {
+				return err
+			}
+			data, err := ioutil.ReadFile(p)
+			if err != nil {
+				return err
+			}
+			return db.addJSON(p, data)
+		})
+		return db, err
+	}
+
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	for _, f := range r.File {
+		if f.FileInfo().IsDir() || filepath.Ext(f.Name) != ".json" {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		data, err := ioutil.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+		if err := db.addJSON(f.Name, data); err != nil {
+			return nil, err
+		}
+	}
+	return db, nil
+}
+
+func (db *OSVDatabase) addJSON(name string, data []byte) error {
+	var a OSVAdvisory
+	if err := json.Unmarshal(data, &a); err != nil {
+		return fmt.Errorf("%s: %v", name, err)
+	}
+	db.Add(&a)
+	return nil
+}
+
+// Add indexes a, unless it was withdrawn.
+func (db *OSVDatabase) Add(a *OSVAdvisory) {
+	if a.Withdrawn != "" {
+		return
+	}
+	seen := map[string]bool{}
+	for _, affected := range a.Affected {
+		if !isMavenEcosystem(affected.Package.Ecosystem) || seen[affected.Package.Name] {
+			continue
+		}
+		seen[affected.Package.Name] = true
+		db.advisories[affected.Package.Name] = append(db.advisories[affected.Package.Name], a)
+	}
+}
+
+// isMavenEcosystem accepts Maven as well as the Maven:<repository url>
+// variants.
+func isMavenEcosystem(ecosystem string) bool {
+	return ecosystem == osvEcosystem || strings.HasPrefix(ecosystem, osvEcosystem+":")
+}
+
+// Len returns the number of indexed artifacts.
+func (db *OSVDatabase) Len() int {
+	return len(db.advisories)
+}
+
+// Advisories returns the advisories affecting version of
+// groupId:artifactId.
+func (db *OSVDatabase) Advisories(groupId, artifactId, version string) []*OSVAdvisory {
+	name := groupId + ":" + artifactId
+	var matches []*OSVAdvisory
+	for _, a := range db.advisories[name] {
+		if a.Affects(name, version) {
+			matches = append(matches, a)
+		}
+	}
+	return matches
+}
+
+// Affects reports whether version of the package name (groupId:artifactId)
+// is affected, either listed explicitly or within one of the ECOSYSTEM
+// ranges, ordered as Maven orders versions.
+func (a *OSVAdvisory) Affects(name, version string) bool {
+	v := ParseVersion(SnapshotBaseVersion(version))
+	for _, affected := range a.Affected {
+		if !isMavenEcosystem(affected.Package.Ecosystem) || affected.Package.Name != name {
+			continue
+		}
+		for _, listed := range affected.Versions {
+			if v.Compare(ParseVersion(listed)) == 0 {
+				return true
+			}
+		}
+		for _, r := range affected.Ranges {
+			if r.Type == "ECOSYSTEM" && r.affects(v) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (r OSVRange) affects(v Version) bool {
+	events := make([]OSVEvent, len(r.Events))
+	copy(events, r.Events)
+	sort.SliceStable(events, func(i, j int) bool {
+		return compareEvents(events[i], events[j]) < 0
+	})
+	affected := false
+	for _, e := range events {
+		switch {
+		case e.Introduced != "":
+			if e.Introduced == "0" || v.Compare(ParseVersion(e.Introduced)) >= 0 {
+				affected = true
+			}
+		case e.Fixed != "":
+			if v.Compare(ParseVersion(e.Fixed)) >= 0 {
+				affected = false
+			}
+		case e.LastAffected != "":
+			if v.Compare(ParseVersion(e.LastAffected)) > 0 {
+				affected = false
+			}
+		case e.Limit != "":
+			if v.Compare(ParseVersion(e.Limit)) >= 0 {
+				affected = false
+			}
+		}
+	}
+	return affected
+}
+
+func (e OSVEvent) version() string {
+	switch {
+	case e.Introduced != "":
+		return e.Introduced
+	case e.Fixed != "":
+		return e.Fixed
+	case e.LastAffected != "":
+		return e.LastAffected
+	}
+	return e.Limit
+}
+
+// compareEvents orders events by version, introduced 0 first.
+func compareEvents(a, b OSVEvent) int {
+	if a.Introduced == "0" || b.Introduced == "0" {
+		if a.Introduced == b.Introduced {
+			return 0
+		}
+		if a.Introduced == "0" {
+			return -1
+		}
+		return 1
+	}
+	return CompareVersions(a.version(), b.version())
+}
+
+// FixedVersions returns the versions of name fixing the advisory, in
+// ascending order.
+func (a *OSVAdvisory) FixedVersions(name string) []string {
+	var fixed []string
+	for _, affected := range a.Affected {
+		if !isMavenEcosystem(affected.Package.Ecosystem) || affected.Package.Name != name {
+			continue
+		}
+		for _, r := range affected.Ranges {
+			for _, e := range r.Events {
+				if e.Fixed != "" && !containsString(fixed, e.Fixed) {
+					fixed = append(fixed, e.Fixed)
+				}
+			}
+		}
+	}
+	sort.Slice(fixed, func(i, j int) bool {
+		return CompareVersions(fixed[i], fixed[j]) < 0
+	})
+	return fixed
+}
+
+type VulnerabilityFinding struct {
+	Node          *DependencyNode
+	Path          []*DependencyNode
+	Advisory      *OSVAdvisory
+	FixedVersions []string
+}
+
+type VulnerabilityReport struct {
+	Findings []VulnerabilityFinding
+}
+
+// Match looks up every artifact of the graph rooted at n, the root included.
+// Each affected artifact is reported once per advisory, with the first path
+// that pulled it in.
+func (db *OSVDatabase) Match(n *DependencyNode) *VulnerabilityReport {
+	report := &VulnerabilityReport{}
+	seen := map[string]bool{}
+	n.Walk(func(node *DependencyNode, path []*DependencyNode) bool {
+		if !node.Included() {
+			return false
+		}
+		c := node.Coordinates()
+		key := c.String()
+		if seen[key] {
+			return true
+		}
+		seen[key] = true
+		name := c.GroupId + ":" + c.ArtifactId
+		for _, a := range db.Advisories(c.GroupId, c.ArtifactId, c.Version) {
+			report.Findings = append(report.Findings, VulnerabilityFinding{
+				Node:          node,
+				Path:          path,
+				Advisory:      a,
+				FixedVersions: a.FixedVersions(name),
+			})
+		}
+		return true
+	})
+	return report
+}
+
+// Text lists the affected artifacts with their advisories, the versions
+// fixing them and the dependency path that pulled them in.
+func (r *VulnerabilityReport) Text() string {
+	var buf bytes.Buffer
+	for _, f := range r.Findings {
+		id := f.Advisory.ID
+		if len(f.Advisory.Aliases) > 0 {
+			id += " (" + strings.Join(f.Advisory.Aliases, ", ") + ")"
+		}
+		fmt.Fprintf(&buf, "%s: %s", f.Node.Coordinates(), id)
+		if f.Advisory.Summary != "" {
+			fmt.Fprintf(&buf, " %s", f.Advisory.Summary)
+		}
+		buf.WriteByte('\n')
+		if len(f.FixedVersions) > 0 {
+			fmt.Fprintf(&buf, "    fixed in %s\n", strings.Join(f.FixedVersions, ", "))
+		} else {
+			buf.WriteString("    no fix available\n")
+		}
+		fmt.Fprintf(&buf, "    via %s\n", FormatPath(f.Path))
+	}
+	return buf.String()
+}
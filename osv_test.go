@@ -0,0 +1,132 @@
+package mvnparse
+
+import (
+	"archive/zip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testGuavaAdvisory = `{
+  "id": "GHSA-7g45-4rm6-3mm3",
+  "aliases": ["CVE-2023-2976"],
+  "summary": "Guava vulnerable to insecure use of temporary directory",
+  "affected": [{
+    "package": {"ecosystem": "Maven", "name": "com.google.guava:guava"},
+    "ranges": [{"type": "ECOSYSTEM", "events": [{"fixed": "32.0.0-android"}, {"introduced": "1.0"}]}]
+  }]
+}`
+
+const testSlf4jAdvisory = `{
+  "id": "GHSA-0000-0000-0000",
+  "affected": [
+    {
+      "package": {"ecosystem": "Maven", "name": "org.slf4j:slf4j-api"},
+      "ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "0"}, {"last_affected": "1.7.36"}]}],
+      "versions": ["2.0.9"]
+    },
+    {
+      "package": {"ecosystem": "npm", "name": "junit"},
+      "ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}]}]
+    }
+  ]
+}`
+
+const testWithdrawnAdvisory = `{
+  "id": "GHSA-1111-1111-1111",
+  "withdrawn": "2023-01-01T00:00:00Z",
+  "affected": [{
+    "package": {"ecosystem": "Maven", "name": "junit:junit"},
+    "ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "0"}]}]
+  }]
+}`
+
+func writeTestOSVZip(t *testing.T, path string, files map[string]string) {
+	f, err := os.Create(path)
+	assert.NoError(t, err)
+	defer f.Close()
+	w := zip.NewWriter(f)
+	for name, content := range files {
+		fw, err := w.Create(name)
+		assert.NoError(t, err)
+		_, err = fw.Write([]byte(content))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, w.Close())
+}
+
+func TestLoadOSVDatabase(t *testing.T) {
+	dir, err := ioutil.TempDir("", "osv")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	files := map[string]string{
+		"GHSA-7g45-4rm6-3mm3.json": testGuavaAdvisory,
+		"GHSA-0000-0000-0000.json": testSlf4jAdvisory,
+		"GHSA-1111-1111-1111.json": testWithdrawnAdvisory,
+	}
+	zipPath := filepath.Join(dir, "all.zip")
+	writeTestOSVZip(t, zipPath, files)
+	extracted := filepath.Join(dir, "extracted")
+	assert.NoError(t, os.MkdirAll(extracted, 0755))
+	for name, content := range files {
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(extracted, name), []byte(content), 0644))
+	}
+
+	for _, path := range []string{zipPath, extracted} {
+		db, err := LoadOSVDatabase(path)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, db.Len())
+		assert.Len(t, db.Advisories("com.google.guava", "guava", "31.1-jre"), 1)
+		assert.Empty(t, db.Advisories("com.google.guava", "guava", "32.0.0-android"))
+		assert.Empty(t, db.Advisories("junit", "junit", "4.13.2"))
+	}
+
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(extracted, "broken.json"), []byte("{"), 0644))
+	_, err = LoadOSVDatabase(extracted)
+	assert.Error(t, err)
+	_, err = LoadOSVDatabase(filepath.Join(dir, "missing.zip"))
+	assert.Error(t, err)
+}
+
+func TestOSVAdvisory_Affects(t *testing.T) {
+	db := NewOSVDatabase()
+	for _, content := range []string{testGuavaAdvisory, testSlf4jAdvisory} {
+		assert.NoError(t, db.addJSON("test", []byte(content)))
+	}
+	slf4j := db.advisories["org.slf4j:slf4j-api"][0]
+	for version, affected := range map[string]bool{
+		"1.7.36":       true,
+		"1.7.36.1":     false,
+		"1.0-SNAPSHOT": true,
+		"2.0.9":        true,
+		"2.0.9.0":      true,
+		"2.0.10":       false,
+	} {
+		assert.Equal(t, affected, slf4j.Affects("org.slf4j:slf4j-api", version), version)
+	}
+	guava := db.advisories["com.google.guava:guava"][0]
+	assert.False(t, guava.Affects("com.google.guava:guava", "1.0-rc1"))
+	assert.True(t, guava.Affects("com.google.guava:guava", "32.0.0-SNAPSHOT"))
+	assert.Equal(t, []string{"32.0.0-android"}, guava.FixedVersions("com.google.guava:guava"))
+	assert.False(t, guava.Affects("com.google.guava:failureaccess", "1.0"))
+}
+
+func TestOSVDatabase_Match(t *testing.T) {
+	db := NewOSVDatabase()
+	for _, content := range []string{testGuavaAdvisory, testSlf4jAdvisory} {
+		assert.NoError(t, db.addJSON("test", []byte(content)))
+	}
+	report := db.Match(testGraph())
+	assert.Len(t, report.Findings, 2)
+	assert.Equal(t, "com.example:app:1.0 -> com.example:lib-a:1.0 -> com.google.guava:guava:31.1-jre", FormatPath(report.Findings[0].Path))
+	assert.Equal(t, `com.google.guava:guava:31.1-jre: GHSA-7g45-4rm6-3mm3 (CVE-2023-2976) Guava vulnerable to insecure use of temporary directory
+    fixed in 32.0.0-android
+    via com.example:app:1.0 -> com.example:lib-a:1.0 -> com.google.guava:guava:31.1-jre
+org.slf4j:slf4j-api:2.0.9: GHSA-0000-0000-0000
+    no fix available
+    via com.example:app:1.0 -> com.example:lib-a:1.0 -> org.slf4j:slf4j-api:2.0.9
+`, report.Text())
+}
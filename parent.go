@@ -0,0 +1,82 @@
+package mvnparse
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ParentChain returns pf followed by its ancestors, nearest first. A parent
+// is read from its relativePath (../pom.xml by default) when the POM found
+// there has the expected coordinates, and from repo otherwise.
+func ParentChain(pf *ProjectFile, repo *LocalRepository) ([]*ProjectFile, error) {
+	chain := []*ProjectFile{pf}
+	seen := map[string]bool{pf.Path: true}
+	for current := pf; current.Project.Parent != nil; {
+		parent, err := loadParent(current, repo)
+		if err != nil {
+			return nil, err
+		}
+		if seen[parent.Path] {
+			return nil, fmt.Errorf("parent cycle at %s", parent.Path)
+		}
+		seen[parent.Path] = true
+		chain = append(chain, parent)
+		current = parent
+	}
+	return chain, nil
+}
+
+func loadParent(pf *ProjectFile, repo *LocalRepository) (*ProjectFile, error) {
+	parent := pf.Project.Parent
+	relativePath := parent.RelativePath
+	if relativePath == "" {
+		relativePath = "../pom.xml"
+	}
+	path := pomPath(filepath.Join(pf.Dir(), filepath.FromSlash(relativePath)))
+	if _, err := os.Stat(path); err == nil {
+		candidate, err := ParseFile(path)
+		if err == nil {
+			c := candidate.Project.Coordinates()
+			if c.GroupId == parent.GroupId && c.ArtifactId == parent.ArtifactId && c.Version == parent.Version {
+				return candidate, nil
+			}
+		}
+	}
+	if repo == nil {
+		return nil, fmt.Errorf("parent %s of %s not found", parent.Coordinates(), pf.Path)
+	}
+	candidate, err := ParseFile(repo.PomPath(parent.Coordinates()))
+	if err != nil {
+		return nil, fmt.Errorf("parent %s of %s not found: %v", parent.Coordinates(), pf.Path, err)
+	}
+	return candidate, nil
+}
+
+// projectChain returns the POM of the node and its ancestors from repo,
+// stopping at the first one that cannot be found.
+func (n *DependencyNode) projectChain(repo *LocalRepository) []*Project {
+	p := n.loadProject(repo)
+	if p == nil {
+		return nil
+	}
+	chain := []*Project{p}
+	if repo == nil {
+		return chain
+	}
+	seen := map[string]bool{}
+	for p.Parent != nil {
+		c := p.Parent.Coordinates()
+		if seen[c.String()] {
+			break
+		}
+		seen[c.String()] = true
+		parent, err := repo.Project(c)
+		if err != nil {
+			break
+		}
+		chain = append(chain, parent)
+		p = parent
+	}
+	return chain
+}
@@ -0,0 +1,82 @@
+package mvnparse
+
+import (
+	"fmt"
+	"strings"
+)
+
+// matchWildcard matches s against pattern, where * stands for any sequence
+// of characters, so org.apache.* matches org.apache.commons.
+func matchWildcard(pattern, s string) bool {
+	if !strings.Contains(pattern, "*") {
+		return pattern == s
+	}
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(s, parts[0]) {
+		return false
+	}
+	s = s[len(parts[0]):]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(s, part)
+		if i < 0 {
+			return false
+		}
+		s = s[i+len(part):]
+	}
+	return strings.HasSuffix(s, parts[len(parts)-1])
+}
+
+func matchAnyWildcard(patterns []string, s string) bool {
+	for _, pattern := range patterns {
+		if matchWildcard(pattern, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchArtifact matches d against an artifact pattern in the form
+// groupId[:artifactId[:version[:type[:scope[:classifier]]]]], each segment
+// possibly using * wildcards and the version a range such as [1.0,2.0).
+// Missing segments match anything, invalid ranges nothing.
+func matchArtifact(pattern string, d Dependency) bool {
+	n := &DependencyNode{Dependency: d}
+	values := []string{d.GroupId, d.ArtifactId, d.Version, n.Coordinates().TypeOrDefault(), n.Scope(), d.Classifier}
+	for i, segment := range strings.Split(pattern, ":") {
+		if i >= len(values) {
+			return false
+		}
+		if i == 2 && isVersionRange(segment) {
+			r, err := ParseVersionRange(segment)
+			if err != nil || !r.Contains(values[i]) {
+				return false
+			}
+			continue
+		}
+		if !matchWildcard(segment, values[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// checkArtifactPattern returns an error when the version range of pattern
+// is invalid.
+func checkArtifactPattern(pattern string) error {
+	segments := strings.Split(pattern, ":")
+	if len(segments) > 2 && isVersionRange(segments[2]) {
+		if _, err := ParseVersionRange(segments[2]); err != nil {
+			return fmt.Errorf("artifact pattern %s: %v", pattern, err)
+		}
+	}
+	return nil
+}
+
+func matchAnyArtifact(patterns []string, d Dependency) bool {
+	for _, pattern := range patterns {
+		if matchArtifact(pattern, d) {
+			return true
+		}
+	}
+	return false
+}
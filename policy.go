@@ -0,0 +1,342 @@
+package mvnparse
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Rule checks a project against an organization policy. Implement it to add
+// rules of your own, and RegisterRule to make them configurable.
+type Rule interface {
+	// Name identifies the rule in configurations and findings.
+	Name() string
+	Check(t *RuleTarget) []Problem
+}
+
+// RuleTarget is the project a rule checks along with its parents, nearest
+// first, when they are known, so that inherited values count.
+type RuleTarget struct {
+	File    *ProjectFile
+	Parents []*ProjectFile
+}
+
+func (t *RuleTarget) Project() *Project {
+	return t.File.Project
+}
+
+// Chain returns the project followed by its parents.
+func (t *RuleTarget) Chain() []*ProjectFile {
+	return append([]*ProjectFile{t.File}, t.Parents...)
+}
+
+// Property returns the value of the property name as defined by the nearest
+// POM of the chain declaring it.
+func (t *RuleTarget) Property(name string) (string, bool) {
+	for _, f := range t.Chain() {
+		if v, ok := f.Project.Properties.Get(name); ok {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// Interpolate resolves the properties of s with the properties of the
+// chain, then the built-in properties of the project, leaving the unknown
+// ones as they are.
+func (t *RuleTarget) Interpolate(s string) string {
+	return interpolate(s, func(name string) (string, bool) {
+		if v, ok := t.Property(name); ok {
+			return v, true
+		}
+		return t.Project().Property(name)
+	}, 0)
+}
+
+// RuleFactory builds a configured rule, decode filling the configuration
+// struct of the rule from the entry of the policy file.
+type RuleFactory func(decode func(v interface{}) error) (Rule, error)
+
+var (
+	ruleFactoriesMu sync.RWMutex
+	ruleFactories   = map[string]RuleFactory{}
+)
+
+// RegisterRule makes a rule available to policy files under name. It panics
+// when name is already registered, like the built-in rules are.
+func RegisterRule(name string, factory RuleFactory) {
+	ruleFactoriesMu.Lock()
+	defer ruleFactoriesMu.Unlock()
+	if _, ok := ruleFactories[name]; ok {
+		panic("mvnparse: rule " + name + " registered twice")
+	}
+	ruleFactories[name] = factory
+}
+
+// RegisteredRules returns the names of the rules policy files may use.
+func RegisteredRules() []string {
+	ruleFactoriesMu.RLock()
+	defer ruleFactoriesMu.RUnlock()
+	names := make([]string, 0, len(ruleFactories))
+	for name := range ruleFactories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Policy is a set of rules evaluated together.
+type Policy struct {
+	Rules []Rule
+}
+
+func NewPolicy(rules ...Rule) *Policy {
+	return &Policy{Rules: rules}
+}
+
+// policyEntry is the part of a rule entry common to every rule, the other
+// keys being the configuration of the rule.
+type policyEntry struct {
+	Rule string `yaml:"rule"`
+	// Severity overrides the severity of the problems of the rule.
+	Severity Severity `yaml:"severity"`
+}
+
+// ParsePolicy reads a policy from YAML or JSON listing the rules to apply:
+//
+//	rules:
+//	  - rule: requireProperties
+//	    properties: [java.version]
+//	  - rule: bannedRepositories
+//	    severity: warning
+//	    urls: ["http://*"]
+//
+// Keys the rule does not know, such as a misspelled option, are errors.
+func ParsePolicy(data []byte) (*Policy, error) {
+	var config struct {
+		Rules []yaml.Node `yaml:"rules"`
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&config); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("invalid policy: %v", err)
+	}
+	policy := &Policy{}
+	for i := range config.Rules {
+		node := &config.Rules[i]
+		var entry policyEntry
+		if err := node.Decode(&entry); err != nil {
+			return nil, fmt.Errorf("invalid policy rule at line %d: %v", node.Line, err)
+		}
+		ruleFactoriesMu.RLock()
+		factory, ok := ruleFactories[entry.Rule]
+		ruleFactoriesMu.RUnlock()
+		if !ok {
+			return nil, fmt.Errorf("unknown policy rule %q at line %d", entry.Rule, node.Line)
+		}
+		switch entry.Severity {
+		case "", SeverityError, SeverityWarning:
+		default:
+			return nil, fmt.Errorf("invalid severity %q for rule %s at line %d", entry.Severity, entry.Rule, node.Line)
+		}
+		rule, err := factory(func(v interface{}) error {
+			if err := checkKnownKeys(node, reflect.TypeOf(v), "rule", "severity"); err != nil {
+				return err
+			}
+			return node.Decode(v)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("rule %s at line %d: %v", entry.Rule, node.Line, err)
+		}
+		if entry.Severity != "" {
+			rule = &severityRule{rule, entry.Severity}
+		}
+		policy.Rules = append(policy.Rules, rule)
+	}
+	return policy, nil
+}
+
+var yamlUnmarshalerType = reflect.TypeOf((*yaml.Unmarshaler)(nil)).Elem()
+
+// checkKnownKeys fails on the first key of node that no field of a value of
+// type t decodes, extra keys aside, like decoders with KnownFields do, but
+// naming the line of the key within the whole policy.
+func checkKnownKeys(node *yaml.Node, t reflect.Type, extra ...string) error {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if reflect.PtrTo(t).Implements(yamlUnmarshalerType) {
+		return nil
+	}
+	switch {
+	case node.Kind == yaml.MappingNode && t.Kind() == reflect.Struct:
+		fields, open := yamlFields(t)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if containsString(extra, key.Value) {
+				continue
+			}
+			field, ok := fields[key.Value]
+			if !ok {
+				if open {
+					continue
+				}
+				return fmt.Errorf("unknown key %q at line %d", key.Value, key.Line)
+			}
+			if err := checkKnownKeys(value, field); err != nil {
+				return err
+			}
+		}
+	case node.Kind == yaml.MappingNode && t.Kind() == reflect.Map:
+		for i := 1; i < len(node.Content); i += 2 {
+			if err := checkKnownKeys(node.Content[i], t.Elem()); err != nil {
+				return err
+			}
+		}
+	case node.Kind == yaml.SequenceNode && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array):
+		for _, item := range node.Content {
+			if err := checkKnownKeys(item, t.Elem()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// yamlFields returns the types of the fields of the struct type t by yaml
+// key, inlined structs included, and whether an inlined map accepts any
+// other key.
+func yamlFields(t reflect.Type) (map[string]reflect.Type, bool) {
+	fields := map[string]reflect.Type{}
+	open := false
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("yaml")
+		if f.PkgPath != "" || tag == "-" {
+			continue
+		}
+		options := strings.Split(tag, ",")
+		if containsString(options[1:], "inline") {
+			inline := f.Type
+			for inline.Kind() == reflect.Ptr {
+				inline = inline.Elem()
+			}
+			if inline.Kind() == reflect.Map {
+				open = true
+				continue
+			}
+			inlined, inlinedOpen := yamlFields(inline)
+			for name, ft := range inlined {
+				fields[name] = ft
+			}
+			open = open || inlinedOpen
+			continue
+		}
+		name := options[0]
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+		fields[name] = f.Type
+	}
+	return fields, open
+}
+
+func LoadPolicy(path string) (*Policy, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := ParsePolicy(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return policy, nil
+}
+
+// severityRule reports the problems of a rule with another severity.
+type severityRule struct {
+	Rule
+	severity Severity
+}
+
+func (r *severityRule) Check(t *RuleTarget) []Problem {
+	problems := r.Rule.Check(t)
+	for i := range problems {
+		problems[i].Severity = r.severity
+	}
+	return problems
+}
+
+// PolicyFinding is a problem found by a rule in a POM.
+type PolicyFinding struct {
+	Rule string
+	File string
+	Problem
+}
+
+type PolicyReport struct {
+	Findings []PolicyFinding
+}
+
+// Failed reports whether any finding is an error.
+func (r *PolicyReport) Failed() bool {
+	for _, f := range r.Findings {
+		if f.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// Check evaluates the rules against p alone.
+func (p *Policy) Check(project *Project) *PolicyReport {
+	return p.CheckTarget(&RuleTarget{File: &ProjectFile{Project: project}})
+}
+
+// CheckTarget evaluates the rules against t.
+func (p *Policy) CheckTarget(t *RuleTarget) *PolicyReport {
+	report := &PolicyReport{}
+	p.check(t, report)
+	return report
+}
+
+// CheckReactor evaluates the rules against every project of r, with its
+// parents from the reactor.
+func (p *Policy) CheckReactor(r *Reactor) *PolicyReport {
+	report := &PolicyReport{}
+	for _, pf := range r.Projects {
+		p.check(&RuleTarget{File: pf, Parents: r.parentChain(pf)[1:]}, report)
+	}
+	return report
+}
+
+func (p *Policy) check(t *RuleTarget, report *PolicyReport) {
+	for _, rule := range p.Rules {
+		for _, problem := range locateProblems(rule.Check(t), t.File.Locations) {
+			report.Findings = append(report.Findings, PolicyFinding{Rule: rule.Name(), File: t.File.Path, Problem: problem})
+		}
+	}
+}
+
+// Text lists the findings, located in their file when known.
+func (r *PolicyReport) Text() string {
+	var buf bytes.Buffer
+	for _, f := range r.Findings {
+		where := f.Path
+		switch {
+		case f.Location != nil:
+			where = f.Location.String()
+		case f.File != "":
+			where = f.File + " " + f.Path
+		}
+		fmt.Fprintf(&buf, "[%s] %s: %s @ %s\n", strings.ToUpper(string(f.Severity)), f.Rule, f.Message, where)
+	}
+	return buf.String()
+}
@@ -0,0 +1,302 @@
+package mvnparse
+
+import (
+	"fmt"
+	"strings"
+)
+
+func init() {
+	RegisterRule("requireProperties", func(decode func(interface{}) error) (Rule, error) {
+		r := &RequirePropertiesRule{}
+		if err := decode(r); err != nil {
+			return nil, err
+		}
+		if len(r.Properties) == 0 {
+			return nil, fmt.Errorf("no properties listed")
+		}
+		return r, nil
+	})
+	RegisterRule("bannedRepositories", func(decode func(interface{}) error) (Rule, error) {
+		r := &BannedRepositoriesRule{}
+		if err := decode(r); err != nil {
+			return nil, err
+		}
+		if len(r.URLs) == 0 && len(r.IDs) == 0 {
+			return nil, fmt.Errorf("no urls or ids listed")
+		}
+		return r, nil
+	})
+	RegisterRule("requireDistributionManagement", func(decode func(interface{}) error) (Rule, error) {
+		r := &RequireDistributionManagementRule{}
+		return r, decode(r)
+	})
+	RegisterRule("requirePluginVersions", func(decode func(interface{}) error) (Rule, error) {
+		r := &RequirePluginVersionsRule{}
+		return r, decode(r)
+	})
+	RegisterRule("bannedPlugins", func(decode func(interface{}) error) (Rule, error) {
+		r := &BannedPluginsRule{}
+		if err := decode(r); err != nil {
+			return nil, err
+		}
+		if len(r.Plugins) == 0 {
+			return nil, fmt.Errorf("no plugins listed")
+		}
+		for _, pattern := range r.Plugins {
+			if err := checkArtifactPattern(pattern); err != nil {
+				return nil, err
+			}
+		}
+		return r, nil
+	})
+}
+
+// RequirePropertiesRule requires properties to be defined by the project or
+// a parent.
+type RequirePropertiesRule struct {
+	Properties []string `json:"properties" yaml:"properties"`
+}
+
+func (r *RequirePropertiesRule) Name() string {
+	return "requireProperties"
+}
+
+func (r *RequirePropertiesRule) Check(t *RuleTarget) []Problem {
+	var problems []Problem
+	for _, name := range r.Properties {
+		if _, ok := t.Property(name); !ok {
+			problems = append(problems, Problem{Severity: SeverityError, Message: fmt.Sprintf("property %s must be defined", name), Path: "/project/properties"})
+		}
+	}
+	return problems
+}
+
+// BannedRepositoriesRule bans the repositories and plugin repositories of
+// the project and its profiles whose URL or id matches one of the patterns,
+// where * is a wildcard, such as http://*.
+type BannedRepositoriesRule struct {
+	URLs []string `json:"urls,omitempty" yaml:"urls"`
+	IDs  []string `json:"ids,omitempty" yaml:"ids"`
+}
+
+func (r *BannedRepositoriesRule) Name() string {
+	return "bannedRepositories"
+}
+
+func (r *BannedRepositoriesRule) Check(t *RuleTarget) []Problem {
+	var problems []Problem
+	check := func(path, id, url string) {
+		switch {
+		case matchAnyWildcard(r.URLs, url):
+			problems = append(problems, Problem{Severity: SeverityError, Message: fmt.Sprintf("repository %s uses banned URL %s", id, url), Path: path + "/url"})
+		case matchAnyWildcard(r.IDs, id):
+			problems = append(problems, Problem{Severity: SeverityError, Message: fmt.Sprintf("repository id %s is banned", id), Path: path + "/id"})
+		}
+	}
+	checkSections := func(path string, repositories *[]Repository, pluginRepositories *[]PluginRepository) {
+		if repositories != nil {
+			for i, repo := range *repositories {
+				check(fmt.Sprintf("%s/repositories/repository[%d]", path, i+1), repo.Id, repo.URL)
+			}
+		}
+		if pluginRepositories != nil {
+			for i, repo := range *pluginRepositories {
+				check(fmt.Sprintf("%s/pluginRepositories/pluginRepository[%d]", path, i+1), repo.Id, repo.URL)
+			}
+		}
+	}
+	p := t.Project()
+	checkSections("/project", p.Repositories, p.PluginRepositories)
+	if p.Profiles != nil {
+		for i, profile := range *p.Profiles {
+			checkSections(fmt.Sprintf("/project/profiles/profile[%d]", i+1), profile.Repositories, profile.PluginRepositories)
+		}
+	}
+	return problems
+}
+
+// RequireDistributionManagementRule requires the project or a parent to
+// declare where releases, and optionally snapshots, are deployed.
+type RequireDistributionManagementRule struct {
+	SnapshotRepository bool `json:"snapshotRepository,omitempty" yaml:"snapshotRepository"`
+}
+
+func (r *RequireDistributionManagementRule) Name() string {
+	return "requireDistributionManagement"
+}
+
+func (r *RequireDistributionManagementRule) Check(t *RuleTarget) []Problem {
+	var repository, snapshotRepository bool
+	for _, f := range t.Chain() {
+		if m := f.Project.DistributionManagement; m != nil {
+			repository = repository || m.Repository != nil
+			snapshotRepository = snapshotRepository || m.SnapshotRepository != nil
+		}
+	}
+	var problems []Problem
+	if !repository {
+		problems = append(problems, Problem{Severity: SeverityError, Message: "distributionManagement.repository is missing", Path: "/project/distributionManagement/repository"})
+	}
+	if r.SnapshotRepository && !snapshotRepository {
+		problems = append(problems, Problem{Severity: SeverityError, Message: "distributionManagement.snapshotRepository is missing", Path: "/project/distributionManagement/snapshotRepository"})
+	}
+	return problems
+}
+
+// RequirePluginVersionsRule requires the build plugins of the project and
+// its profiles to have a version, declared or managed by the project or a
+// parent, optionally banning snapshots and the LATEST and RELEASE
+// meta versions.
+type RequirePluginVersionsRule struct {
+	BanSnapshots bool `json:"banSnapshots,omitempty" yaml:"banSnapshots"`
+	BanLatest    bool `json:"banLatest,omitempty" yaml:"banLatest"`
+	// Excludes lists groupId:artifactId patterns of plugins not checked.
+	Excludes []string `json:"excludes,omitempty" yaml:"excludes"`
+}
+
+func (r *RequirePluginVersionsRule) Name() string {
+	return "requirePluginVersions"
+}
+
+func (r *RequirePluginVersionsRule) Check(t *RuleTarget) []Problem {
+	var problems []Problem
+	p := t.Project()
+	for _, s := range buildSections(p) {
+		if s.build.PluginManagement != nil {
+			for i, plugin := range s.build.PluginManagement.Plugins {
+				if plugin.Version != "" {
+					problems = r.checkVersion(problems, t, fmt.Sprintf("%s/pluginManagement/plugins/plugin[%d]/version", s.path, i+1), plugin, plugin.Version)
+				}
+			}
+		}
+		if s.build.Plugins == nil {
+			continue
+		}
+		for i, plugin := range *s.build.Plugins {
+			path := fmt.Sprintf("%s/plugins/plugin[%d]", s.path, i+1)
+			if plugin.Version != "" {
+				problems = r.checkVersion(problems, t, path+"/version", plugin, plugin.Version)
+				continue
+			}
+			if r.excluded(plugin) {
+				continue
+			}
+			if managedPluginVersion(s.build, t.Chain(), pluginKey(plugin)) == "" {
+				problems = append(problems, Problem{Severity: SeverityError, Message: fmt.Sprintf("plugin %s has no version", pluginKey(plugin)), Path: path})
+			}
+		}
+	}
+	return problems
+}
+
+func (r *RequirePluginVersionsRule) excluded(plugin Plugin) bool {
+	c := plugin.Coordinates()
+	return matchAnyArtifact(r.Excludes, Dependency{GroupId: c.GroupId, ArtifactId: c.ArtifactId, Version: c.Version})
+}
+
+func (r *RequirePluginVersionsRule) checkVersion(problems []Problem, t *RuleTarget, path string, plugin Plugin, version string) []Problem {
+	if r.excluded(plugin) {
+		return problems
+	}
+	v := t.Interpolate(version)
+	switch {
+	case strings.Contains(v, "${"):
+		problems = append(problems, Problem{Severity: SeverityError, Message: fmt.Sprintf("plugin %s version %s cannot be resolved", pluginKey(plugin), v), Path: path})
+	case r.BanSnapshots && strings.HasSuffix(v, "-SNAPSHOT"):
+		problems = append(problems, Problem{Severity: SeverityError, Message: fmt.Sprintf("plugin %s uses snapshot version %s", pluginKey(plugin), v), Path: path})
+	case r.BanLatest && (v == "LATEST" || v == "RELEASE"):
+		problems = append(problems, Problem{Severity: SeverityError, Message: fmt.Sprintf("plugin %s uses meta version %s", pluginKey(plugin), v), Path: path})
+	}
+	return problems
+}
+
+// BannedPluginsRule bans the build plugins of the project and its profiles
+// matching groupId:artifactId[:version] patterns, with * wildcards and
+// version ranges such as [,3.0). Versions are resolved with the properties
+// of the parents; a version left unresolved is reported when a pattern with
+// a version matches the groupId and artifactId of the plugin.
+type BannedPluginsRule struct {
+	Plugins []string `json:"plugins" yaml:"plugins"`
+}
+
+func (r *BannedPluginsRule) Name() string {
+	return "bannedPlugins"
+}
+
+func (r *BannedPluginsRule) Check(t *RuleTarget) []Problem {
+	var problems []Problem
+	p := t.Project()
+	for _, s := range buildSections(p) {
+		if s.build.Plugins == nil {
+			continue
+		}
+		for i, plugin := range *s.build.Plugins {
+			c := plugin.Coordinates()
+			version := plugin.Version
+			if version == "" {
+				version = managedPluginVersion(s.build, t.Chain(), pluginKey(plugin))
+			}
+			version = t.Interpolate(version)
+			d := Dependency{GroupId: c.GroupId, ArtifactId: c.ArtifactId, Version: version}
+			path := fmt.Sprintf("%s/plugins/plugin[%d]", s.path, i+1)
+			for _, pattern := range r.Plugins {
+				segments := strings.SplitN(pattern, ":", 3)
+				if len(segments) == 3 && strings.Contains(version, "${") {
+					if matchArtifact(segments[0]+":"+segments[1], d) {
+						problems = append(problems, Problem{Severity: SeverityError, Message: fmt.Sprintf("plugin %s version %s cannot be resolved to check against %s", pluginKey(plugin), version, pattern), Path: path})
+						break
+					}
+					continue
+				}
+				if matchArtifact(pattern, d) {
+					problems = append(problems, Problem{Severity: SeverityError, Message: fmt.Sprintf("plugin %s is banned by %s", pluginKey(plugin), pattern), Path: path})
+					break
+				}
+			}
+		}
+	}
+	return problems
+}
+
+type buildSection struct {
+	path  string
+	build *BuildBase
+}
+
+// buildSections returns the build of p and of its profiles.
+func buildSections(p *Project) []buildSection {
+	var sections []buildSection
+	if p.Build != nil {
+		sections = append(sections, buildSection{"/project/build", &p.Build.BuildBase})
+	}
+	if p.Profiles != nil {
+		for i, profile := range *p.Profiles {
+			if profile.Build != nil {
+				sections = append(sections, buildSection{fmt.Sprintf("/project/profiles/profile[%d]/build", i+1), profile.Build})
+			}
+		}
+	}
+	return sections
+}
+
+// managedPluginVersion returns the version the plugin management of build,
+// then of chain, gives to the plugin key.
+func managedPluginVersion(build *BuildBase, chain []*ProjectFile, key string) string {
+	builds := []*BuildBase{build}
+	for _, f := range chain {
+		if f.Project.Build != nil {
+			builds = append(builds, &f.Project.Build.BuildBase)
+		}
+	}
+	for _, b := range builds {
+		if b.PluginManagement == nil {
+			continue
+		}
+		for _, m := range b.PluginManagement.Plugins {
+			if pluginKey(m) == key && m.Version != "" {
+				return m.Version
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,179 @@
+package mvnparse
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testPolicyPom = `<project>
+  <modelVersion>4.0.0</modelVersion>
+  <parent>
+    <groupId>com.example</groupId>
+    <artifactId>parent</artifactId>
+    <version>1.0</version>
+  </parent>
+  <artifactId>app</artifactId>
+  <properties>
+    <java.version>17</java.version>
+  </properties>
+  <repositories>
+    <repository>
+      <id>internal</id>
+      <url>https://repo.example.com/maven</url>
+    </repository>
+    <repository>
+      <id>legacy</id>
+      <url>http://old.example.com/maven</url>
+    </repository>
+  </repositories>
+  <build>
+    <plugins>
+      <plugin>
+        <artifactId>maven-compiler-plugin</artifactId>
+      </plugin>
+      <plugin>
+        <artifactId>maven-surefire-plugin</artifactId>
+      </plugin>
+      <plugin>
+        <groupId>org.example</groupId>
+        <artifactId>custom-plugin</artifactId>
+        <version>1.0-SNAPSHOT</version>
+      </plugin>
+    </plugins>
+  </build>
+  <profiles>
+    <profile>
+      <id>ant</id>
+      <pluginRepositories>
+        <pluginRepository>
+          <id>snapshots</id>
+          <url>https://snapshots.example.com</url>
+        </pluginRepository>
+      </pluginRepositories>
+      <build>
+        <plugins>
+          <plugin>
+            <artifactId>maven-antrun-plugin</artifactId>
+            <version>LATEST</version>
+          </plugin>
+        </plugins>
+      </build>
+    </profile>
+  </profiles>
+</project>`
+
+const testPolicyParentPom = `<project>
+  <modelVersion>4.0.0</modelVersion>
+  <groupId>com.example</groupId>
+  <artifactId>parent</artifactId>
+  <version>1.0</version>
+  <properties>
+    <encoding>UTF-8</encoding>
+  </properties>
+  <distributionManagement>
+    <repository>
+      <id>releases</id>
+      <url>https://repo.example.com/releases</url>
+    </repository>
+  </distributionManagement>
+  <build>
+    <pluginManagement>
+      <plugins>
+        <plugin>
+          <artifactId>maven-compiler-plugin</artifactId>
+          <version>3.11.0</version>
+        </plugin>
+      </plugins>
+    </pluginManagement>
+  </build>
+</project>`
+
+func testPolicyTarget(t *testing.T) *RuleTarget {
+	p, err := ParseStr(testPolicyPom)
+	assert.NoError(t, err)
+	parent, err := ParseStr(testPolicyParentPom)
+	assert.NoError(t, err)
+	return &RuleTarget{File: &ProjectFile{Project: p}, Parents: []*ProjectFile{{Project: parent}}}
+}
+
+func TestRequirePropertiesRule(t *testing.T) {
+	r := &RequirePropertiesRule{Properties: []string{"java.version", "encoding", "license"}}
+	assert.Equal(t, []Problem{
+		{Severity: SeverityError, Message: "property license must be defined", Path: "/project/properties"},
+	}, r.Check(testPolicyTarget(t)))
+}
+
+func TestBannedRepositoriesRule(t *testing.T) {
+	r := &BannedRepositoriesRule{URLs: []string{"http://*"}, IDs: []string{"snap*"}}
+	assert.Equal(t, []Problem{
+		{Severity: SeverityError, Message: "repository legacy uses banned URL http://old.example.com/maven", Path: "/project/repositories/repository[2]/url"},
+		{Severity: SeverityError, Message: "repository id snapshots is banned", Path: "/project/profiles/profile[1]/pluginRepositories/pluginRepository[1]/id"},
+	}, r.Check(testPolicyTarget(t)))
+}
+
+func TestRequireDistributionManagementRule(t *testing.T) {
+	target := testPolicyTarget(t)
+	r := &RequireDistributionManagementRule{}
+	assert.Empty(t, r.Check(target))
+	r.SnapshotRepository = true
+	assert.Equal(t, []Problem{
+		{Severity: SeverityError, Message: "distributionManagement.snapshotRepository is missing", Path: "/project/distributionManagement/snapshotRepository"},
+	}, r.Check(target))
+
+	target.Parents = nil
+	assert.Len(t, r.Check(target), 2)
+}
+
+func TestRequirePluginVersionsRule(t *testing.T) {
+	r := &RequirePluginVersionsRule{BanSnapshots: true, BanLatest: true}
+	assert.Equal(t, []Problem{
+		{Severity: SeverityError, Message: "plugin org.apache.maven.plugins:maven-surefire-plugin has no version", Path: "/project/build/plugins/plugin[2]"},
+		{Severity: SeverityError, Message: "plugin org.example:custom-plugin uses snapshot version 1.0-SNAPSHOT", Path: "/project/build/plugins/plugin[3]/version"},
+		{Severity: SeverityError, Message: "plugin org.apache.maven.plugins:maven-antrun-plugin uses meta version LATEST", Path: "/project/profiles/profile[1]/build/plugins/plugin[1]/version"},
+	}, r.Check(testPolicyTarget(t)))
+
+	r = &RequirePluginVersionsRule{Excludes: []string{"*:maven-surefire-plugin"}}
+	assert.Empty(t, r.Check(testPolicyTarget(t)))
+
+	// versions are resolved with the properties of the parents
+	target := testPolicyTarget(t)
+	(*target.File.Project.Profiles)[0].Build.Plugins = &[]Plugin{{ArtifactId: "maven-antrun-plugin", Version: "${antrun.version}"}}
+	target.Parents[0].Project.SetProperty("antrun.version", "3.1-SNAPSHOT")
+	r = &RequirePluginVersionsRule{BanSnapshots: true, Excludes: []string{"*:maven-surefire-plugin", "*:custom-plugin"}}
+	assert.Equal(t, []Problem{
+		{Severity: SeverityError, Message: "plugin org.apache.maven.plugins:maven-antrun-plugin uses snapshot version 3.1-SNAPSHOT", Path: "/project/profiles/profile[1]/build/plugins/plugin[1]/version"},
+	}, r.Check(target))
+
+	(*target.File.Project.Profiles)[0].Build.Plugins = &[]Plugin{{ArtifactId: "maven-antrun-plugin", Version: "${missing.version}"}}
+	assert.Equal(t, []Problem{
+		{Severity: SeverityError, Message: "plugin org.apache.maven.plugins:maven-antrun-plugin version ${missing.version} cannot be resolved", Path: "/project/profiles/profile[1]/build/plugins/plugin[1]/version"},
+	}, r.Check(target))
+}
+
+func TestBannedPluginsRule(t *testing.T) {
+	r := &BannedPluginsRule{Plugins: []string{"*:maven-antrun-plugin", "org.apache.maven.plugins:maven-compiler-plugin:[,3.8)"}}
+	assert.Equal(t, []Problem{
+		{Severity: SeverityError, Message: "plugin org.apache.maven.plugins:maven-antrun-plugin is banned by *:maven-antrun-plugin", Path: "/project/profiles/profile[1]/build/plugins/plugin[1]"},
+	}, r.Check(testPolicyTarget(t)))
+
+	// the version managed by the parent is below the bound
+	r.Plugins = []string{"*:maven-compiler-plugin:[,3.12)"}
+	assert.Len(t, r.Check(testPolicyTarget(t)), 1)
+
+	// the version is a property of the parent
+	target := testPolicyTarget(t)
+	(*target.File.Project.Profiles)[0].Build.Plugins = &[]Plugin{{ArtifactId: "maven-antrun-plugin", Version: "${antrun.version}"}}
+	target.Parents[0].Project.SetProperty("antrun.version", "1.8")
+	r.Plugins = []string{"*:maven-antrun-plugin:[,1.5)"}
+	assert.Empty(t, r.Check(target))
+	r.Plugins = []string{"*:maven-antrun-plugin:[1.5,3.0)"}
+	assert.Equal(t, []Problem{
+		{Severity: SeverityError, Message: "plugin org.apache.maven.plugins:maven-antrun-plugin is banned by *:maven-antrun-plugin:[1.5,3.0)", Path: "/project/profiles/profile[1]/build/plugins/plugin[1]"},
+	}, r.Check(target))
+
+	(*target.File.Project.Profiles)[0].Build.Plugins = &[]Plugin{{ArtifactId: "maven-antrun-plugin", Version: "${missing.version}"}}
+	assert.Equal(t, []Problem{
+		{Severity: SeverityError, Message: "plugin org.apache.maven.plugins:maven-antrun-plugin version ${missing.version} cannot be resolved to check against *:maven-antrun-plugin:[1.5,3.0)", Path: "/project/profiles/profile[1]/build/plugins/plugin[1]"},
+	}, r.Check(target))
+}
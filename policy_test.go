@@ -0,0 +1,115 @@
+package mvnparse
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// groupIdRule is a custom rule requiring a groupId prefix.
+type groupIdRule struct {
+	Prefix string `yaml:"prefix"`
+}
+
+func (r *groupIdRule) Name() string {
+	return "groupIdPrefix"
+}
+
+func (r *groupIdRule) Check(t *RuleTarget) []Problem {
+	c := t.Project().Coordinates()
+	if len(c.GroupId) < len(r.Prefix) || c.GroupId[:len(r.Prefix)] != r.Prefix {
+		return []Problem{{Severity: SeverityError, Message: "groupId must start with " + r.Prefix, Path: "/project/groupId"}}
+	}
+	return nil
+}
+
+func init() {
+	RegisterRule("groupIdPrefix", func(decode func(interface{}) error) (Rule, error) {
+		r := &groupIdRule{}
+		return r, decode(r)
+	})
+}
+
+func TestParsePolicy(t *testing.T) {
+	policy, err := ParsePolicy([]byte(`
+rules:
+  - rule: requireProperties
+    properties: [java.version]
+  - rule: groupIdPrefix
+    severity: warning
+    prefix: org.
+`))
+	assert.NoError(t, err)
+	assert.Len(t, policy.Rules, 2)
+	assert.Equal(t, &RequirePropertiesRule{Properties: []string{"java.version"}}, policy.Rules[0])
+	assert.Equal(t, "groupIdPrefix", policy.Rules[1].Name())
+
+	report := policy.Check(&Project{GroupId: "com.example", ArtifactId: "app"})
+	assert.True(t, report.Failed())
+	assert.Equal(t, `[ERROR] requireProperties: property java.version must be defined @ /project/properties
+[WARNING] groupIdPrefix: groupId must start with org. @ /project/groupId
+`, report.Text())
+
+	// JSON is YAML too
+	policy, err = ParsePolicy([]byte(`{"rules": [{"rule": "bannedPlugins", "plugins": ["*:maven-antrun-plugin"]}]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, &BannedPluginsRule{Plugins: []string{"*:maven-antrun-plugin"}}, policy.Rules[0])
+
+	for _, config := range []string{
+		"rules: [{rule: unknown}]",
+		"rules: [{rule: requireProperties}]",
+		"rules: [{rule: requireDistributionManagement, severity: fatal}]",
+		"rules: [{rule: bannedPlugins, plugins: ['a:b:[2,1]']}]",
+		"rules: {}",
+	} {
+		_, err := ParsePolicy([]byte(config))
+		assert.Error(t, err, config)
+	}
+
+	// a misspelled option is an error naming its line
+	_, err = ParsePolicy([]byte(`
+rules:
+  - rule: requirePluginVersions
+    severity: warning
+    banSnapshot: true
+`))
+	assert.EqualError(t, err, `rule requirePluginVersions at line 3: unknown key "banSnapshot" at line 5`)
+	_, err = ParsePolicy([]byte("rule: [{rule: requireDistributionManagement}]"))
+	assert.Error(t, err)
+}
+
+func TestRegisteredRules(t *testing.T) {
+	assert.Equal(t, []string{
+		"bannedPlugins", "bannedRepositories", "groupIdPrefix", "requireDistributionManagement",
+		"requirePluginVersions", "requireProperties",
+	}, RegisteredRules())
+	assert.Panics(t, func() {
+		RegisterRule("requireProperties", nil)
+	})
+}
+
+func TestPolicy_CheckReactor(t *testing.T) {
+	dir := writeTestReactor(t)
+	defer os.RemoveAll(dir)
+	r, err := LoadReactor(dir)
+	assert.NoError(t, err)
+	path := filepath.Join(dir, "policy.yaml")
+	assert.NoError(t, ioutil.WriteFile(path, []byte("rules:\n  - rule: requireProperties\n    properties: [jackson.version]\n  - rule: requireDistributionManagement\n"), 0644))
+
+	policy, err := LoadPolicy(path)
+	assert.NoError(t, err)
+	report := policy.CheckReactor(r)
+	// jackson.version is inherited from the root
+	assert.Len(t, report.Findings, 3)
+	for i, pf := range r.Projects {
+		assert.Equal(t, "requireDistributionManagement", report.Findings[i].Rule)
+		assert.Equal(t, pf.Path, report.Findings[i].File)
+	}
+	assert.Equal(t, &Location{r.Root.Path, 1, 1}, report.Findings[0].Location)
+
+	_, err = LoadPolicy(filepath.Join(dir, "missing.yaml"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,87 @@
+package mvnparse
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/elliotchance/orderedmap"
+)
+
+var propertyReference = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+// Get returns the value of the property name.
+func (p *Properties) Get(name string) (string, bool) {
+	if p == nil {
+		return "", false
+	}
+	v, ok := p.Entries.Get(name)
+	if !ok {
+		return "", false
+	}
+	return fmt.Sprint(v), true
+}
+
+// SetProperty sets the property name, creating the properties section when
+// needed.
+func (p *Project) SetProperty(name, value string) {
+	if p.Properties == nil {
+		p.Properties = &Properties{Entries: *orderedmap.NewOrderedMap()}
+	}
+	p.Properties.Entries.Set(name, value)
+}
+
+// Property returns the value of a property as seen from the POM: the
+// project.* coordinates, then the properties section. Properties inherited
+// from parents are not resolved.
+func (p *Project) Property(name string) (string, bool) {
+	c := p.Coordinates()
+	switch name {
+	case "project.groupId", "pom.groupId":
+		return c.GroupId, c.GroupId != ""
+	case "project.artifactId", "pom.artifactId":
+		return c.ArtifactId, c.ArtifactId != ""
+	case "project.version", "pom.version", "version":
+		return c.Version, c.Version != ""
+	case "project.parent.groupId":
+		if p.Parent != nil {
+			return p.Parent.GroupId, true
+		}
+	case "project.parent.version":
+		if p.Parent != nil {
+			return p.Parent.Version, true
+		}
+	}
+	return p.Properties.Get(name)
+}
+
+// Interpolate replaces the ${...} references of s with the properties of p,
+// recursively. Unknown references are left as is.
+func (p *Project) Interpolate(s string) string {
+	return interpolate(s, p.Property, 0)
+}
+
+// propertyName returns the name of the property s only consists of, as in
+// ${guava.version}.
+func propertyName(s string) (string, bool) {
+	m := propertyReference.FindStringSubmatchIndex(s)
+	if m == nil || m[0] != 0 || m[1] != len(s) {
+		return "", false
+	}
+	return s[m[2]:m[3]], true
+}
+
+// maxInterpolationDepth stops self-referencing properties.
+const maxInterpolationDepth = 16
+
+func interpolate(s string, lookup func(string) (string, bool), depth int) string {
+	if depth >= maxInterpolationDepth {
+		return s
+	}
+	return propertyReference.ReplaceAllStringFunc(s, func(ref string) string {
+		v, ok := lookup(ref[2 : len(ref)-1])
+		if !ok {
+			return ref
+		}
+		return interpolate(v, lookup, depth+1)
+	})
+}
@@ -0,0 +1,38 @@
+package mvnparse
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProject_Interpolate(t *testing.T) {
+	p, err := ParseStr(`<project>
+  <parent>
+    <groupId>com.example</groupId>
+    <artifactId>parent</artifactId>
+    <version>2.0</version>
+  </parent>
+  <artifactId>app</artifactId>
+  <properties>
+    <jackson.version>2.15.2</jackson.version>
+    <jackson.bom>${jackson.version}</jackson.bom>
+    <loop>${loop}</loop>
+  </properties>
+</project>`)
+	assert.NoError(t, err)
+	assert.Equal(t, "2.15.2", p.Interpolate("${jackson.bom}"))
+	assert.Equal(t, "com.example:app:2.0", p.Interpolate("${project.groupId}:${project.artifactId}:${project.version}"))
+	assert.Equal(t, "${missing}-2.0", p.Interpolate("${missing}-${project.parent.version}"))
+	assert.Equal(t, "${loop}", p.Interpolate("${loop}"))
+
+	p = &Project{}
+	p.SetProperty("revision", "1.2")
+	assert.Equal(t, "1.2", p.Interpolate("${revision}"))
+
+	name, ok := propertyName("${jackson.version}")
+	assert.True(t, ok)
+	assert.Equal(t, "jackson.version", name)
+	_, ok = propertyName("${jackson.version}-1")
+	assert.False(t, ok)
+}
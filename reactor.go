@@ -0,0 +1,169 @@
+package mvnparse
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+// ProjectFile is a POM together with the file it was read from.
+type ProjectFile struct {
+	Path    string
+	Project *Project
+	// Modules are the projects aggregated by this one through <modules>.
+	Modules []*ProjectFile
+	// Locations are the positions of the elements as read, not updated by
+	// edits.
+	Locations *Locations
+	// edits are the element texts changed since the POM was read, by path.
+	edits map[string]string
+}
+
+// Reactor is a multi module build, loaded by following <modules> from the
+// root POM.
+type Reactor struct {
+	Root *ProjectFile
+	// Projects lists every project of the reactor, root first, in the order
+	// the modules are declared.
+	Projects []*ProjectFile
+	// config holds the -D options of .mvn/maven.config next to the root.
+	config *mavenConfig
+}
+
+// ParseFile parses the POM at path, or at path/pom.xml when path is a
+// directory.
+func ParseFile(path string) (*ProjectFile, error) {
+	path = pomPath(path)
+	project, locations, err := ParseWithLocations(path)
+	if err != nil {
+		return nil, err
+	}
+	return &ProjectFile{Path: path, Project: project, Locations: locations}, nil
+}
+
+// Dir returns the directory holding the POM.
+func (pf *ProjectFile) Dir() string {
+	return filepath.Dir(pf.Path)
+}
+
+func LoadReactor(path string) (*Reactor, error) {
+	r := &Reactor{}
+	root, err := r.load(pomPath(path), map[string]*ProjectFile{})
+	if err != nil {
+		return nil, err
+	}
+	r.Root = root
+	if r.config, err = loadMavenConfig(root.Dir()); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *Reactor) load(path string, seen map[string]*ProjectFile) (*ProjectFile, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	if pf, ok := seen[abs]; ok {
+		return pf, nil
+	}
+	pf, err := ParseFile(path)
+	if err != nil {
+		return nil, err
+	}
+	seen[abs] = pf
+	r.Projects = append(r.Projects, pf)
+	if pf.Project.Modules == nil {
+		return pf, nil
+	}
+	for _, module := range *pf.Project.Modules {
+		child, err := r.load(pomPath(filepath.Join(pf.Dir(), filepath.FromSlash(module))), seen)
+		if err != nil {
+			return nil, err
+		}
+		pf.Modules = append(pf.Modules, child)
+	}
+	return pf, nil
+}
+
+// Find returns the reactor project with the given groupId and artifactId.
+func (r *Reactor) Find(groupId, artifactId string) *ProjectFile {
+	for _, pf := range r.Projects {
+		c := pf.Project.Coordinates()
+		if c.GroupId == groupId && c.ArtifactId == artifactId {
+			return pf
+		}
+	}
+	return nil
+}
+
+func pomPath(path string) string {
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		return filepath.Join(path, "pom.xml")
+	}
+	return path
+}
+
+// mavenConfig is a .mvn/maven.config file, whose -Dname=value options
+// define user properties overriding the ones of the POMs.
+type mavenConfig struct {
+	path    string
+	data    []byte
+	changed bool
+}
+
+var mavenConfigProperty = regexp.MustCompile(`(?:^|\s)-D\s*([^\s=]+)=(\S*)`)
+
+func loadMavenConfig(dir string) (*mavenConfig, error) {
+	path := filepath.Join(dir, ".mvn", "maven.config")
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &mavenConfig{path: path, data: data}, nil
+}
+
+// value returns the offsets of the value of the last option defining the
+// property name, the one Maven keeps.
+func (c *mavenConfig) value(name string) (int, int, bool) {
+	if c == nil {
+		return 0, 0, false
+	}
+	start, end, ok := 0, 0, false
+	for _, m := range mavenConfigProperty.FindAllSubmatchIndex(c.data, -1) {
+		if string(c.data[m[2]:m[3]]) == name {
+			start, end, ok = m[4], m[5], true
+		}
+	}
+	return start, end, ok
+}
+
+func (c *mavenConfig) Get(name string) (string, bool) {
+	start, end, ok := c.value(name)
+	if !ok {
+		return "", false
+	}
+	return string(c.data[start:end]), true
+}
+
+// set changes the value of the property name, which must be defined.
+func (c *mavenConfig) set(name, value string) {
+	start, end, _ := c.value(name)
+	c.data = append(c.data[:start:start], append([]byte(value), c.data[end:]...)...)
+	c.changed = true
+}
+
+func (c *mavenConfig) write() error {
+	if c == nil || !c.changed {
+		return nil
+	}
+	if err := ioutil.WriteFile(c.path, c.data, 0644); err != nil {
+		return err
+	}
+	c.changed = false
+	return nil
+}
@@ -0,0 +1,128 @@
+package mvnparse
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testRootPom = `<project>
+  <modelVersion>4.0.0</modelVersion>
+  <groupId>com.example</groupId>
+  <artifactId>root</artifactId>
+  <version>1.0-SNAPSHOT</version>
+  <packaging>pom</packaging>
+  <scm>
+    <tag>HEAD</tag>
+  </scm>
+  <modules>
+    <module>core</module>
+    <module>app</module>
+  </modules>
+  <properties>
+    <jackson.version>2.15.2</jackson.version>
+  </properties>
+  <dependencyManagement>
+    <dependencies>
+      <dependency>
+        <groupId>com.example</groupId>
+        <artifactId>core</artifactId>
+        <version>1.0-SNAPSHOT</version>
+      </dependency>
+      <dependency>
+        <groupId>com.fasterxml.jackson.core</groupId>
+        <artifactId>jackson-databind</artifactId>
+        <version>${jackson.version}</version>
+      </dependency>
+    </dependencies>
+  </dependencyManagement>
+</project>`
+
+const testCorePom = `<project>
+  <modelVersion>4.0.0</modelVersion>
+  <parent>
+    <groupId>com.example</groupId>
+    <artifactId>root</artifactId>
+    <version>1.0-SNAPSHOT</version>
+  </parent>
+  <artifactId>core</artifactId>
+  <dependencies>
+    <dependency>
+      <groupId>com.fasterxml.jackson.core</groupId>
+      <artifactId>jackson-databind</artifactId>
+    </dependency>
+    <dependency>
+      <groupId>junit</groupId>
+      <artifactId>junit</artifactId>
+      <version>4.13.2</version>
+      <scope>test</scope>
+    </dependency>
+  </dependencies>
+</project>`
+
+const testAppPom = `<project>
+  <modelVersion>4.0.0</modelVersion>
+  <parent>
+    <groupId>com.example</groupId>
+    <artifactId>root</artifactId>
+    <version>1.0-SNAPSHOT</version>
+  </parent>
+  <artifactId>app</artifactId>
+  <dependencies>
+    <dependency>
+      <groupId>com.example</groupId>
+      <artifactId>core</artifactId>
+      <version>${project.version}</version>
+    </dependency>
+  </dependencies>
+</project>`
+
+// writeTestFiles writes files, keyed by slash separated path, under a new
+// temporary directory.
+func writeTestFiles(t *testing.T, files map[string]string) string {
+	dir, err := ioutil.TempDir("", "mvnparse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func writeTestReactor(t *testing.T) string {
+	return writeTestFiles(t, map[string]string{
+		"pom.xml":      testRootPom,
+		"core/pom.xml": testCorePom,
+		"app/pom.xml":  testAppPom,
+	})
+}
+
+func TestLoadReactor(t *testing.T) {
+	dir := writeTestReactor(t)
+	defer os.RemoveAll(dir)
+
+	r, err := LoadReactor(dir)
+	assert.NoError(t, err)
+	assert.Len(t, r.Projects, 3)
+	assert.Equal(t, "root", r.Root.Project.ArtifactId)
+	assert.Len(t, r.Root.Modules, 2)
+	assert.Equal(t, filepath.Join(dir, "app", "pom.xml"), r.Root.Modules[1].Path)
+
+	core := r.Find("com.example", "core")
+	assert.NotNil(t, core)
+	assert.Equal(t, "1.0-SNAPSHOT", core.Project.Coordinates().Version)
+	assert.Nil(t, r.Find("com.example", "missing"))
+
+	_, err = LoadReactor(filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,234 @@
+package mvnparse
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type VersionIncrement string
+
+const (
+	IncrementMajor     VersionIncrement = "major"
+	IncrementMinor     VersionIncrement = "minor"
+	IncrementPatch     VersionIncrement = "patch"
+	IncrementQualifier VersionIncrement = "qualifier"
+)
+
+// ReleaseOptions configure PrepareRelease. Every field is optional.
+type ReleaseOptions struct {
+	// ReleaseVersion is the current version without -SNAPSHOT by default.
+	ReleaseVersion string
+	// DevelopmentVersion is the release version incremented by Increment,
+	// with -SNAPSHOT, by default.
+	DevelopmentVersion string
+	// Increment is IncrementPatch by default.
+	Increment VersionIncrement
+	// Tag is <root artifactId>-<release version> by default.
+	Tag string
+}
+
+// Release is a prepared release of a reactor: WriteRelease writes the
+// release POMs, to be committed and tagged by the caller, then
+// WriteNextDevelopment moves the reactor to the next development version.
+type Release struct {
+	Reactor            *Reactor
+	Version            string
+	DevelopmentVersion string
+	Tag                string
+}
+
+var releaseVersion = regexp.MustCompile(`^(\d+)(?:\.(\d+))?(?:\.(\d+))?(.*)$`)
+
+// PrepareRelease computes the release and next development versions of r,
+// failing if the reactor is not a snapshot or depends on snapshots outside
+// of it. Nothing is written yet.
+func (r *Reactor) PrepareRelease(opts ReleaseOptions) (*Release, error) {
+	root := r.Root.Project
+	current := r.interpolate(r.Root, root.Coordinates().Version)
+	rel := &Release{Reactor: r, Version: opts.ReleaseVersion, DevelopmentVersion: opts.DevelopmentVersion, Tag: opts.Tag}
+	if rel.Version == "" {
+		if !strings.HasSuffix(current, snapshotSuffix) {
+			return nil, fmt.Errorf("%s: version %s is not a snapshot", r.Root.Path, current)
+		}
+		rel.Version = strings.TrimSuffix(current, snapshotSuffix)
+	}
+	if IsSnapshot(rel.Version) {
+		return nil, fmt.Errorf("release version %s is a snapshot", rel.Version)
+	}
+	if rel.DevelopmentVersion == "" {
+		increment := opts.Increment
+		if increment == "" {
+			increment = IncrementPatch
+		}
+		next, err := NextVersion(rel.Version, increment)
+		if err != nil {
+			return nil, err
+		}
+		rel.DevelopmentVersion = next + snapshotSuffix
+	}
+	if rel.Tag == "" {
+		rel.Tag = root.ArtifactId + "-" + rel.Version
+	}
+	if snapshots := r.externalSnapshots(); len(snapshots) > 0 {
+		return nil, fmt.Errorf("snapshots outside of the reactor:\n  %s", strings.Join(snapshots, "\n  "))
+	}
+	return rel, nil
+}
+
+// NextVersion increments version, keeping its segment count and qualifier:
+// the patch of 1.2 is 1.2.1, the minor of 1.2.3-jre is 1.3.0-jre and the
+// qualifier of 1.0-RC1 is 1.0-RC2.
+func NextVersion(version string, increment VersionIncrement) (string, error) {
+	if increment == IncrementQualifier {
+		i := strings.LastIndexFunc(version, func(r rune) bool { return r < '0' || r > '9' })
+		number := version[i+1:]
+		if i < 0 || number == "" || releaseVersion.FindStringSubmatch(version)[4] == "" {
+			return "", fmt.Errorf("version %s has no qualifier number to increment", version)
+		}
+		n, err := strconv.Atoi(number)
+		if err != nil {
+			return "", err
+		}
+		return version[:i+1] + strconv.Itoa(n+1), nil
+	}
+
+	m := releaseVersion.FindStringSubmatch(version)
+	if m == nil {
+		return "", fmt.Errorf("version %s does not start with a number", version)
+	}
+	count := 1
+	segments := make([]int, 3)
+	for i := range segments {
+		if m[i+1] == "" {
+			continue
+		}
+		n, err := strconv.Atoi(m[i+1])
+		if err != nil {
+			return "", err
+		}
+		segments[i] = n
+		count = i + 1
+	}
+	switch increment {
+	case IncrementMajor:
+		segments = []int{segments[0] + 1, 0, 0}
+	case IncrementMinor:
+		segments = []int{segments[0], segments[1] + 1, 0}
+		count = maxInt(count, 2)
+	case IncrementPatch:
+		segments[2]++
+		count = 3
+	default:
+		return "", fmt.Errorf("unknown increment %q", increment)
+	}
+	parts := make([]string, count)
+	for i := range parts {
+		parts[i] = strconv.Itoa(segments[i])
+	}
+	return strings.Join(parts, ".") + m[4], nil
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// WriteRelease sets the release version and the SCM tag of every project
+// declaring an SCM, and writes the changed POMs.
+func (rel *Release) WriteRelease() ([]*ProjectFile, error) {
+	return rel.write(rel.Version, rel.Tag)
+}
+
+// WriteNextDevelopment sets the next development version, restores the SCM
+// tags to HEAD and writes the changed POMs.
+func (rel *Release) WriteNextDevelopment() ([]*ProjectFile, error) {
+	return rel.write(rel.DevelopmentVersion, "HEAD")
+}
+
+// write applies the version and the SCM tag to the models, then writes
+// each changed POM once.
+func (rel *Release) write(version, tag string) ([]*ProjectFile, error) {
+	r := rel.Reactor
+	changed, err := r.setVersion(version)
+	if err != nil {
+		return nil, err
+	}
+	for _, pf := range r.Projects {
+		scm := pf.Project.SCM
+		if scm == nil || scm.Tag == tag {
+			continue
+		}
+		scm.Tag = tag
+		pf.setText("/project/scm/tag", tag)
+		if !containsProjectFile(changed, pf) {
+			changed = append(changed, pf)
+		}
+	}
+	for _, pf := range changed {
+		if err := pf.Write(); err != nil {
+			return nil, err
+		}
+	}
+	return changed, r.config.write()
+}
+
+// externalSnapshots lists the snapshot parents, dependencies, plugins and
+// extensions, those of the profiles included, that are not built by the
+// reactor.
+func (r *Reactor) externalSnapshots() []string {
+	var snapshots []string
+	for _, pf := range r.Projects {
+		p := pf.Project
+		check := func(kind string, c Coordinates) {
+			c.GroupId = r.interpolate(pf, c.GroupId)
+			c.ArtifactId = r.interpolate(pf, c.ArtifactId)
+			c.Version = r.interpolate(pf, c.Version)
+			if IsSnapshot(c.Version) && r.Find(c.GroupId, c.ArtifactId) == nil {
+				snapshots = append(snapshots, fmt.Sprintf("%s: %s %s:%s:%s", pf.Path, kind, c.GroupId, c.ArtifactId, c.Version))
+			}
+		}
+		if p.Parent != nil {
+			check("parent", p.Parent.Coordinates())
+		}
+		for _, l := range dependencyLists(p) {
+			for _, d := range *l.deps {
+				check("dependency", d.Coordinates())
+			}
+		}
+		for _, declaration := range projectPlugins(p) {
+			plugin := declaration.plugin
+			check("plugin", plugin.Coordinates())
+			if plugin.Dependencies != nil {
+				for _, d := range *plugin.Dependencies {
+					check("plugin dependency", d.Coordinates())
+				}
+			}
+		}
+		if p.Build != nil && p.Build.Extensions != nil {
+			for _, e := range *p.Build.Extensions {
+				check("extension", e.Coordinates())
+			}
+		}
+	}
+	return snapshots
+}
+
+// interpolate resolves the properties of s as seen from pf, looking up the
+// options of .mvn/maven.config, then the properties of its ancestors within
+// the reactor.
+func (r *Reactor) interpolate(pf *ProjectFile, s string) string {
+	chain := r.parentChain(pf)
+	return interpolate(s, func(name string) (string, bool) {
+		if v, ok := r.config.Get(name); ok {
+			return v, true
+		}
+		if owner := chainPropertyOwner(chain, name); owner != nil {
+			return owner.Project.Properties.Get(name)
+		}
+		return pf.Project.Property(name)
+	}, 0)
+}
@@ -0,0 +1,147 @@
+package mvnparse
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNextVersion(t *testing.T) {
+	for _, tc := range []struct {
+		version   string
+		increment VersionIncrement
+		next      string
+	}{
+		{"1.2.3", IncrementPatch, "1.2.4"},
+		{"1.2", IncrementPatch, "1.2.1"},
+		{"1.2.3", IncrementMinor, "1.3.0"},
+		{"1", IncrementMinor, "1.1"},
+		{"1.2.3", IncrementMajor, "2.0.0"},
+		{"31.1-jre", IncrementMajor, "32.0-jre"},
+		{"1.0-RC1", IncrementQualifier, "1.0-RC2"},
+		{"1.0-beta-9", IncrementQualifier, "1.0-beta-10"},
+	} {
+		next, err := NextVersion(tc.version, tc.increment)
+		assert.NoError(t, err)
+		assert.Equal(t, tc.next, next, "%s %s", tc.version, tc.increment)
+	}
+	for _, tc := range []struct {
+		version   string
+		increment VersionIncrement
+	}{
+		{"1.0.1", IncrementQualifier},
+		{"1.0-final", IncrementQualifier},
+		{"beta", IncrementPatch},
+		{"1.0", "build"},
+	} {
+		_, err := NextVersion(tc.version, tc.increment)
+		assert.Error(t, err, "%s %s", tc.version, tc.increment)
+	}
+}
+
+func TestReactor_PrepareRelease(t *testing.T) {
+	dir := writeTestReactor(t)
+	defer os.RemoveAll(dir)
+	r, err := LoadReactor(dir)
+	assert.NoError(t, err)
+
+	rel, err := r.PrepareRelease(ReleaseOptions{Increment: IncrementMinor})
+	assert.NoError(t, err)
+	assert.Equal(t, "1.0", rel.Version)
+	assert.Equal(t, "1.1-SNAPSHOT", rel.DevelopmentVersion)
+	assert.Equal(t, "root-1.0", rel.Tag)
+
+	changed, err := rel.WriteRelease()
+	assert.NoError(t, err)
+	assert.Len(t, changed, 3)
+	root, err := Parse(filepath.Join(dir, "pom.xml"))
+	assert.NoError(t, err)
+	assert.Equal(t, "1.0", root.Version)
+	assert.Equal(t, "root-1.0", root.SCM.Tag)
+
+	_, err = r.PrepareRelease(ReleaseOptions{})
+	assert.Error(t, err)
+
+	changed, err = rel.WriteNextDevelopment()
+	assert.NoError(t, err)
+	assert.Len(t, changed, 3)
+	r, err = LoadReactor(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "1.1-SNAPSHOT", r.Find("com.example", "core").Project.Coordinates().Version)
+	assert.Equal(t, "HEAD", r.Root.Project.SCM.Tag)
+}
+
+func TestReactor_PrepareRelease_Snapshots(t *testing.T) {
+	dir := writeTestReactor(t)
+	defer os.RemoveAll(dir)
+	r, err := LoadReactor(dir)
+	assert.NoError(t, err)
+	r.Root.Project.SetProperty("jackson.version", "2.16.0-SNAPSHOT")
+	core := r.Find("com.example", "core")
+	core.Project.Build = &Build{BuildBase: BuildBase{Plugins: &[]Plugin{{ArtifactId: "maven-shade-plugin", Version: "3.6.0-SNAPSHOT"}}}}
+
+	_, err = r.PrepareRelease(ReleaseOptions{})
+	assert.EqualError(t, err, `snapshots outside of the reactor:
+  `+r.Root.Path+`: dependency com.fasterxml.jackson.core:jackson-databind:2.16.0-SNAPSHOT
+  `+core.Path+`: plugin org.apache.maven.plugins:maven-shade-plugin:3.6.0-SNAPSHOT`)
+}
+
+func TestReactor_PrepareRelease_ProfileSnapshots(t *testing.T) {
+	dir := writeTestReactor(t)
+	defer os.RemoveAll(dir)
+	r, err := LoadReactor(dir)
+	assert.NoError(t, err)
+	core := r.Find("com.example", "core")
+	core.Project.Profiles = &[]Profile{
+		{Id: "extra", Dependencies: &[]Dependency{{GroupId: "o", ArtifactId: "lib", Version: "2.0-SNAPSHOT"}}},
+		{Id: "managed", DependencyManagement: &DependencyManagement{Dependencies: &[]Dependency{{GroupId: "o", ArtifactId: "bom", Version: "3.0-SNAPSHOT", Type: "pom", Scope: ScopeImport}}}},
+	}
+
+	_, err = r.PrepareRelease(ReleaseOptions{})
+	assert.EqualError(t, err, `snapshots outside of the reactor:
+  `+core.Path+`: dependency o:lib:2.0-SNAPSHOT
+  `+core.Path+`: dependency o:bom:3.0-SNAPSHOT`)
+}
+
+func TestRelease_WriteRelease_KeepsFormatting(t *testing.T) {
+	pom := `<?xml version="1.0" encoding="UTF-8"?>
+<project xmlns="http://maven.apache.org/POM/4.0.0" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance"
+         xsi:schemaLocation="http://maven.apache.org/POM/4.0.0 https://maven.apache.org/xsd/maven-4.0.0.xsd">
+  <groupId>com.example</groupId>
+  <artifactId>tool</artifactId>
+  <version>2.0-SNAPSHOT</version>
+  <!-- the description is escaped -->
+  <description>Rewrites &lt;version&gt; &amp; tags</description>
+  <scm>
+    <connection>scm:git:https://example.com/tool.git</connection>
+  </scm>
+</project>
+`
+	dir := writeTestFiles(t, map[string]string{"pom.xml": pom})
+	defer os.RemoveAll(dir)
+	r, err := LoadReactor(dir)
+	assert.NoError(t, err)
+	rel, err := r.PrepareRelease(ReleaseOptions{})
+	assert.NoError(t, err)
+
+	changed, err := rel.WriteRelease()
+	assert.NoError(t, err)
+	assert.Equal(t, []*ProjectFile{r.Root}, changed)
+	data, err := ioutil.ReadFile(r.Root.Path)
+	assert.NoError(t, err)
+	expected := strings.Replace(pom, "2.0-SNAPSHOT", "2.0", 1)
+	expected = strings.Replace(expected, "tool.git</connection>\n", "tool.git</connection>\n    <tag>tool-2.0</tag>\n", 1)
+	assert.Equal(t, expected, string(data))
+
+	_, err = rel.WriteNextDevelopment()
+	assert.NoError(t, err)
+	data, err = ioutil.ReadFile(r.Root.Path)
+	assert.NoError(t, err)
+	expected = strings.Replace(pom, "2.0-SNAPSHOT", "2.0.1-SNAPSHOT", 1)
+	expected = strings.Replace(expected, "tool.git</connection>\n", "tool.git</connection>\n    <tag>HEAD</tag>\n", 1)
+	assert.Equal(t, expected, string(data))
+}
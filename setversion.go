@@ -0,0 +1,228 @@
+package mvnparse
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SetVersion changes the version of the reactor root to version, like
+// versions:set, along with every module sharing the old version, the
+// parents of the modules and the dependencies, managed dependencies and
+// plugins referencing reactor projects, profiles included. A version defined
+// by a property, such as the CI friendly ${revision}, is changed where the
+// property is defined: in .mvn/maven.config or the POM declaring it.
+// ${sha1} and ${changelist} around it are kept, as are ${project.version}
+// references, which follow by themselves. It writes and returns the changed
+// POMs.
+func (r *Reactor) SetVersion(version string) ([]*ProjectFile, error) {
+	changed, err := r.setVersion(version)
+	if err != nil {
+		return nil, err
+	}
+	for _, pf := range changed {
+		if err := pf.Write(); err != nil {
+			return nil, err
+		}
+	}
+	return changed, r.config.write()
+}
+
+// setVersion applies SetVersion to the models and records the edits,
+// without writing anything.
+func (r *Reactor) setVersion(version string) ([]*ProjectFile, error) {
+	root := r.Root.Project
+	from := r.interpolate(r.Root, root.Coordinates().Version)
+	if from == "" || strings.Contains(from, "${") {
+		return nil, fmt.Errorf("%s: cannot resolve the current version %q", r.Root.Path, root.Coordinates().Version)
+	}
+	s := &versionSetter{reactor: r, from: from, to: version}
+
+	for _, pf := range r.Projects {
+		p := pf.Project
+		if p.Version != "" {
+			if err := s.set(pf, "/project/version", &p.Version); err != nil {
+				return nil, err
+			}
+		}
+		if p.Parent != nil && r.Find(p.Parent.GroupId, p.Parent.ArtifactId) != nil {
+			if err := s.set(pf, "/project/parent/version", &p.Parent.Version); err != nil {
+				return nil, err
+			}
+		}
+		for _, l := range dependencyLists(p) {
+			for i := range *l.deps {
+				d := &(*l.deps)[i]
+				if d.Version != "" && s.inReactor(p, d.GroupId, d.ArtifactId) {
+					if err := s.set(pf, fmt.Sprintf("%s[%d]/version", l.path, i+1), &d.Version); err != nil {
+						return nil, err
+					}
+				}
+			}
+		}
+		for _, declaration := range projectPlugins(p) {
+			plugin := declaration.plugin
+			c := plugin.Coordinates()
+			if plugin.Version != "" && s.inReactor(p, c.GroupId, c.ArtifactId) {
+				if err := s.set(pf, declaration.path+"/version", &plugin.Version); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	return s.changed, nil
+}
+
+type versionSetter struct {
+	reactor  *Reactor
+	from, to string
+	changed  []*ProjectFile
+}
+
+func (s *versionSetter) inReactor(p *Project, groupId, artifactId string) bool {
+	return s.reactor.Find(p.Interpolate(groupId), p.Interpolate(artifactId)) != nil
+}
+
+// ciFriendlySuffixes are the properties a CI friendly version may add
+// around ${revision}, left alone when changing the version.
+var ciFriendlySuffixes = []string{"sha1", "changelist"}
+
+// set replaces the old version held by value, the text of the element at
+// path of pf, or by the property it references.
+func (s *versionSetter) set(pf *ProjectFile, path string, value *string) error {
+	v := strings.TrimSpace(*value)
+	if !strings.Contains(v, "${") {
+		if v == s.from && v != s.to {
+			*value = s.to
+			pf.setText(path, s.to)
+			s.mark(pf)
+		}
+		return nil
+	}
+	if resolved := s.reactor.interpolate(pf, v); resolved != s.from && !strings.Contains(resolved, "${") {
+		return nil
+	}
+	name, prefix, suffix, err := s.versionProperty(pf, v)
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(s.to, prefix) || !strings.HasSuffix(s.to, suffix) || len(s.to) < len(prefix)+len(suffix) {
+		return fmt.Errorf("%s: cannot set version %s with %q, whose other properties give %q and %q", pf.Path, s.to, v, prefix, suffix)
+	}
+	from := strings.TrimSuffix(strings.TrimPrefix(s.from, prefix), suffix)
+	to := strings.TrimSuffix(strings.TrimPrefix(s.to, prefix), suffix)
+
+	chain := s.reactor.parentChain(pf)
+	seen := map[string]bool{}
+	for {
+		if seen[name] {
+			return fmt.Errorf("%s: property %s references itself", pf.Path, name)
+		}
+		seen[name] = true
+		config := s.reactor.config
+		if v, ok := config.Get(name); ok {
+			if v == from && from != to {
+				config.set(name, to)
+			}
+			return nil
+		}
+		owner := chainPropertyOwner(chain, name)
+		if owner == nil {
+			if _, builtin := pf.Project.Property(name); builtin {
+				// ${project.version} and alike follow the project
+				return nil
+			}
+			return fmt.Errorf("%s: property %s is not defined in the reactor", pf.Path, name)
+		}
+		v, _ := owner.Project.Properties.Get(name)
+		next, ok := propertyName(strings.TrimSpace(v))
+		if !ok {
+			if strings.TrimSpace(v) == from && from != to {
+				owner.Project.SetProperty(name, to)
+				owner.setText("/project/properties/"+name, to)
+				s.mark(owner)
+			}
+			return nil
+		}
+		name = next
+	}
+}
+
+// versionProperty splits a version made of property references, such as
+// ${revision}${sha1}${changelist}, into the property holding the version
+// and the values of the CI friendly properties before and after it.
+func (s *versionSetter) versionProperty(pf *ProjectFile, v string) (name, prefix, suffix string, err error) {
+	var names []string
+	for rest := v; rest != ""; {
+		end := strings.Index(rest, "}")
+		if !strings.HasPrefix(rest, "${") || end < 0 {
+			return "", "", "", fmt.Errorf("%s: cannot update version %q mixing properties and text", pf.Path, v)
+		}
+		names = append(names, rest[2:end])
+		rest = rest[end+1:]
+	}
+	index := -1
+	for i, n := range names {
+		if containsString(ciFriendlySuffixes, n) {
+			continue
+		}
+		if index >= 0 {
+			return "", "", "", fmt.Errorf("%s: cannot update version %q made of several properties", pf.Path, v)
+		}
+		index = i
+	}
+	if index < 0 {
+		return "", "", "", fmt.Errorf("%s: version %q has no property to update", pf.Path, v)
+	}
+	resolve := func(names []string) string {
+		var buf strings.Builder
+		for _, n := range names {
+			buf.WriteString("${" + n + "}")
+		}
+		return s.reactor.interpolate(pf, buf.String())
+	}
+	return names[index], resolve(names[:index]), resolve(names[index+1:]), nil
+}
+
+func (s *versionSetter) mark(pf *ProjectFile) {
+	if !containsProjectFile(s.changed, pf) {
+		s.changed = append(s.changed, pf)
+	}
+}
+
+// parentChain returns pf followed by its ancestors within the reactor.
+func (r *Reactor) parentChain(pf *ProjectFile) []*ProjectFile {
+	chain := []*ProjectFile{pf}
+	for current := pf; current.Project.Parent != nil; {
+		parent := r.Find(current.Project.Parent.GroupId, current.Project.Parent.ArtifactId)
+		if parent == nil || containsProjectFile(chain, parent) {
+			break
+		}
+		chain = append(chain, parent)
+		current = parent
+	}
+	return chain
+}
+
+type pluginDeclaration struct {
+	path   string
+	plugin *Plugin
+}
+
+// projectPlugins returns the build plugins and managed plugins of p and of
+// its profiles.
+func projectPlugins(p *Project) []pluginDeclaration {
+	var plugins []pluginDeclaration
+	for _, s := range buildSections(p) {
+		if s.build.Plugins != nil {
+			for i := range *s.build.Plugins {
+				plugins = append(plugins, pluginDeclaration{fmt.Sprintf("%s/plugins/plugin[%d]", s.path, i+1), &(*s.build.Plugins)[i]})
+			}
+		}
+		if s.build.PluginManagement != nil {
+			for i := range s.build.PluginManagement.Plugins {
+				plugins = append(plugins, pluginDeclaration{fmt.Sprintf("%s/pluginManagement/plugins/plugin[%d]", s.path, i+1), &s.build.PluginManagement.Plugins[i]})
+			}
+		}
+	}
+	return plugins
+}
@@ -0,0 +1,195 @@
+package mvnparse
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReactor_SetVersion(t *testing.T) {
+	dir := writeTestReactor(t)
+	defer os.RemoveAll(dir)
+	r, err := LoadReactor(dir)
+	assert.NoError(t, err)
+
+	changed, err := r.SetVersion("1.1-SNAPSHOT")
+	assert.NoError(t, err)
+	assert.Len(t, changed, 3)
+
+	r, err = LoadReactor(dir)
+	assert.NoError(t, err)
+	for _, pf := range r.Projects {
+		assert.Equal(t, "1.1-SNAPSHOT", pf.Project.Coordinates().Version, pf.Path)
+	}
+	managed, err := r.Root.Project.ManagedDependency("com.example:core")
+	assert.NoError(t, err)
+	assert.Equal(t, "1.1-SNAPSHOT", managed.Version)
+	jackson, err := r.Root.Project.ManagedDependency("com.fasterxml.jackson.core:jackson-databind")
+	assert.NoError(t, err)
+	assert.Equal(t, "${jackson.version}", jackson.Version)
+	core, err := r.Find("com.example", "app").Project.Dependency("com.example:core")
+	assert.NoError(t, err)
+	assert.Equal(t, "${project.version}", core.Version)
+
+	changed, err = r.SetVersion("1.1-SNAPSHOT")
+	assert.NoError(t, err)
+	assert.Empty(t, changed)
+}
+
+func TestReactor_SetVersion_Revision(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{
+		"pom.xml": `<project>
+  <groupId>com.example</groupId>
+  <artifactId>root</artifactId>
+  <version>${revision}</version>
+  <packaging>pom</packaging>
+  <modules>
+    <module>core</module>
+    <module>tool</module>
+  </modules>
+  <properties>
+    <revision>2.0-SNAPSHOT</revision>
+    <core.version>${revision}</core.version>
+  </properties>
+</project>`,
+		"core/pom.xml": `<project>
+  <parent>
+    <groupId>com.example</groupId>
+    <artifactId>root</artifactId>
+    <version>${revision}</version>
+  </parent>
+  <artifactId>core</artifactId>
+</project>`,
+		"tool/pom.xml": `<project>
+  <parent>
+    <groupId>com.example</groupId>
+    <artifactId>root</artifactId>
+    <version>${revision}</version>
+  </parent>
+  <artifactId>tool</artifactId>
+  <version>0.9</version>
+  <dependencies>
+    <dependency>
+      <groupId>com.example</groupId>
+      <artifactId>core</artifactId>
+      <version>${core.version}</version>
+    </dependency>
+  </dependencies>
+</project>`,
+	})
+	defer os.RemoveAll(dir)
+	r, err := LoadReactor(dir)
+	assert.NoError(t, err)
+
+	changed, err := r.SetVersion("2.0")
+	assert.NoError(t, err)
+	assert.Equal(t, []*ProjectFile{r.Root}, changed)
+	root, err := Parse(filepath.Join(dir, "pom.xml"))
+	assert.NoError(t, err)
+	assert.Equal(t, "${revision}", root.Version)
+	assert.Equal(t, "2.0", root.Interpolate("${core.version}"))
+	tool, err := Parse(filepath.Join(dir, "tool", "pom.xml"))
+	assert.NoError(t, err)
+	assert.Equal(t, "0.9", tool.Version)
+
+	r.Root.Project.Version = "${revision}-${changelist}"
+	_, err = r.SetVersion("2.1")
+	assert.Error(t, err)
+}
+
+func TestReactor_SetVersion_CIFriendly(t *testing.T) {
+	rootPom := `<?xml version="1.0" encoding="UTF-8"?>
+<project xmlns="http://maven.apache.org/POM/4.0.0">
+  <groupId>com.example</groupId>
+  <artifactId>root</artifactId>
+  <!-- set by CI -->
+  <version>${revision}${sha1}${changelist}</version>
+  <packaging>pom</packaging>
+  <modules>
+    <module>core</module>
+  </modules>
+  <properties>
+    <revision>1.0</revision>
+    <sha1/>
+    <changelist>-SNAPSHOT</changelist>
+  </properties>
+</project>
+`
+	corePom := `<project>
+  <parent>
+    <groupId>com.example</groupId>
+    <artifactId>root</artifactId>
+    <version>${revision}${changelist}</version>
+  </parent>
+  <artifactId>core</artifactId>
+  <profiles>
+    <profile>
+      <id>self</id>
+      <dependencies>
+        <dependency>
+          <groupId>com.example</groupId>
+          <artifactId>root</artifactId>
+          <version>1.0-SNAPSHOT</version>
+          <type>pom</type>
+        </dependency>
+      </dependencies>
+      <build>
+        <plugins>
+          <plugin>
+            <groupId>com.example</groupId>
+            <artifactId>core</artifactId>
+            <version>1.0-SNAPSHOT</version>
+          </plugin>
+        </plugins>
+      </build>
+    </profile>
+  </profiles>
+</project>
+`
+	dir := writeTestFiles(t, map[string]string{
+		"pom.xml":      rootPom,
+		"core/pom.xml": corePom,
+	})
+	defer os.RemoveAll(dir)
+	r, err := LoadReactor(dir)
+	assert.NoError(t, err)
+
+	changed, err := r.SetVersion("1.1-SNAPSHOT")
+	assert.NoError(t, err)
+	assert.Len(t, changed, 2)
+	data, err := ioutil.ReadFile(filepath.Join(dir, "pom.xml"))
+	assert.NoError(t, err)
+	assert.Equal(t, strings.Replace(rootPom, "<revision>1.0<", "<revision>1.1<", 1), string(data))
+	data, err = ioutil.ReadFile(filepath.Join(dir, "core", "pom.xml"))
+	assert.NoError(t, err)
+	assert.Equal(t, strings.Replace(corePom, "1.0-SNAPSHOT", "1.1-SNAPSHOT", -1), string(data))
+
+	// ${changelist} is kept, a release needs it emptied
+	_, err = r.SetVersion("1.1")
+	assert.Error(t, err)
+}
+
+func TestReactor_SetVersion_MavenConfig(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{
+		"pom.xml": `<project>
+  <groupId>com.example</groupId>
+  <artifactId>root</artifactId>
+  <version>${revision}</version>
+</project>`,
+		".mvn/maven.config": "-B\n-Drevision=3.0-SNAPSHOT -Dsha1=\n",
+	})
+	defer os.RemoveAll(dir)
+	r, err := LoadReactor(dir)
+	assert.NoError(t, err)
+
+	changed, err := r.SetVersion("3.1-SNAPSHOT")
+	assert.NoError(t, err)
+	assert.Empty(t, changed)
+	data, err := ioutil.ReadFile(filepath.Join(dir, ".mvn", "maven.config"))
+	assert.NoError(t, err)
+	assert.Equal(t, "-B\n-Drevision=3.1-SNAPSHOT -Dsha1=\n", string(data))
+}
@@ -0,0 +1,333 @@
+package mvnparse
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	spdxVersion     = "SPDX-2.3"
+	spdxNoAssertion = "NOASSERTION"
+	spdxDocumentId  = "SPDXRef-DOCUMENT"
+)
+
+var spdxIdInvalidChars = regexp.MustCompile(`[^A-Za-z0-9.\-]+`)
+
+// SPDXDocument is an SPDX 2.3 document, written as JSON or tag-value.
+type SPDXDocument struct {
+	SPDXVersion                string                 `json:"spdxVersion"`
+	DataLicense                string                 `json:"dataLicense"`
+	SPDXID                     string                 `json:"SPDXID"`
+	Name                       string                 `json:"name"`
+	DocumentNamespace          string                 `json:"documentNamespace"`
+	CreationInfo               SPDXCreationInfo       `json:"creationInfo"`
+	Packages                   []SPDXPackage          `json:"packages"`
+	Relationships              []SPDXRelationship     `json:"relationships"`
+	HasExtractedLicensingInfos []SPDXExtractedLicense `json:"hasExtractedLicensingInfos,omitempty"`
+}
+
+type SPDXCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type SPDXPackage struct {
+	SPDXID           string            `json:"SPDXID"`
+	Name             string            `json:"name"`
+	VersionInfo      string            `json:"versionInfo,omitempty"`
+	Supplier         string            `json:"supplier,omitempty"`
+	DownloadLocation string            `json:"downloadLocation"`
+	FilesAnalyzed    bool              `json:"filesAnalyzed"`
+	Checksums        []SPDXChecksum    `json:"checksums,omitempty"`
+	HomePage         string            `json:"homepage,omitempty"`
+	LicenseConcluded string            `json:"licenseConcluded"`
+	LicenseDeclared  string            `json:"licenseDeclared"`
+	CopyrightText    string            `json:"copyrightText"`
+	Description      string            `json:"description,omitempty"`
+	ExternalRefs     []SPDXExternalRef `json:"externalRefs,omitempty"`
+}
+
+type SPDXChecksum struct {
+	Algorithm     string `json:"algorithm"`
+	ChecksumValue string `json:"checksumValue"`
+}
+
+type SPDXExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type SPDXRelationship struct {
+	SPDXElementId      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+type SPDXExtractedLicense struct {
+	LicenseId     string   `json:"licenseId"`
+	ExtractedText string   `json:"extractedText"`
+	Name          string   `json:"name,omitempty"`
+	SeeAlsos      []string `json:"seeAlsos,omitempty"`
+}
+
+// NewSPDX builds an SPDX document of the graph rooted at n. POMs and
+// artifact files missing from the nodes are read from repo, which may be nil.
+// Test scoped dependencies are related with DEV_DEPENDENCY_OF, others with
+// DEPENDS_ON. Licenses are inherited from parent POMs and given by SPDX
+// identifier when they map reliably to one, as LicenseRef- otherwise. They
+// are declared, not concluded: the concluded license is NOASSERTION.
+func NewSPDX(n *DependencyNode, repo *LocalRepository) (*SPDXDocument, error) {
+	serial, err := newUUID()
+	if err != nil {
+		return nil, err
+	}
+	name := n.Coordinates().String()
+	doc := &SPDXDocument{
+		SPDXVersion:       spdxVersion,
+		DataLicense:       "CC0-1.0",
+		SPDXID:            spdxDocumentId,
+		Name:              name,
+		DocumentNamespace: "https://spdx.org/spdxdocs/" + spdxIdInvalidChars.ReplaceAllString(name, "-") + "-" + serial,
+		CreationInfo: SPDXCreationInfo{
+			Created:  time.Now().UTC().Format(time.RFC3339),
+			Creators: []string{"Tool: mvnparser"},
+		},
+		Packages:      []SPDXPackage{},
+		Relationships: []SPDXRelationship{},
+	}
+	refs := &spdxLicenseRefs{}
+	packages := map[string]bool{}
+	relationships := map[SPDXRelationship]bool{}
+	addRelationship := func(r SPDXRelationship) {
+		if !relationships[r] {
+			relationships[r] = true
+			doc.Relationships = append(doc.Relationships, r)
+		}
+	}
+
+	var walkErr error
+	n.Walk(func(node *DependencyNode, path []*DependencyNode) bool {
+		if walkErr != nil || !node.Included() {
+			return false
+		}
+		id := spdxPackageId(node.Coordinates())
+		if len(path) == 1 {
+			addRelationship(SPDXRelationship{spdxDocumentId, "DESCRIBES", id})
+		} else {
+			parent := spdxPackageId(path[len(path)-2].Coordinates())
+			if node.Scope() == ScopeTest {
+				addRelationship(SPDXRelationship{id, "DEV_DEPENDENCY_OF", parent})
+			} else {
+				addRelationship(SPDXRelationship{parent, "DEPENDS_ON", id})
+			}
+		}
+		if packages[id] {
+			return true
+		}
+		packages[id] = true
+
+		pkg := SPDXPackage{
+			SPDXID:           id,
+			Name:             node.Dependency.ArtifactId,
+			VersionInfo:      node.Dependency.Version,
+			Supplier:         spdxNoAssertion,
+			DownloadLocation: spdxNoAssertion,
+			LicenseConcluded: spdxNoAssertion,
+			LicenseDeclared:  spdxNoAssertion,
+			CopyrightText:    spdxNoAssertion,
+			ExternalRefs: []SPDXExternalRef{{
+				ReferenceCategory: "PACKAGE-MANAGER",
+				ReferenceType:     "purl",
+				ReferenceLocator:  node.Coordinates().PackageURL(),
+			}},
+		}
+		if p := node.loadProject(repo); p != nil {
+			if p.Name != "" {
+				pkg.Name = p.Name
+			}
+			pkg.Description = p.Description
+			pkg.HomePage = p.URL
+			if p.Organization != nil && p.Organization.Name != "" {
+				pkg.Supplier = "Organization: " + p.Organization.Name
+			}
+		}
+		if licenses := node.licenses(repo); len(licenses) > 0 {
+			var ids []string
+			for _, m := range licenses {
+				if m.Confidence >= LicenseHigh {
+					id := m.ID
+					if len(licenses) > 1 && strings.Contains(id, " ") {
+						id = "(" + id + ")"
+					}
+					ids = append(ids, id)
+					continue
+				}
+				info, added := refs.ref(m.License)
+				ids = append(ids, info.LicenseId)
+				if added {
+					doc.HasExtractedLicensingInfos = append(doc.HasExtractedLicensingInfos, info)
+				}
+			}
+			// several licenses in a POM are a choice between them
+			pkg.LicenseDeclared = strings.Join(ids, " OR ")
+		}
+		if len(path) > 1 {
+			if file, err := node.artifactFile(repo); err == nil {
+				hashes, err := fileHashes(file)
+				if err != nil && !os.IsNotExist(err) {
+					walkErr = err
+					return false
+				}
+				for _, h := range hashes {
+					pkg.Checksums = append(pkg.Checksums, SPDXChecksum{
+						Algorithm:     strings.Replace(h.Alg, "-", "", -1),
+						ChecksumValue: h.Content,
+					})
+				}
+			}
+		}
+		doc.Packages = append(doc.Packages, pkg)
+		return true
+	})
+	if walkErr != nil {
+		return nil, walkErr
+	}
+	return doc, nil
+}
+
+func spdxPackageId(c Coordinates) string {
+	id := c.GroupId + "-" + c.ArtifactId + "-" + c.Version
+	if c.Classifier != "" {
+		id += "-" + c.Classifier
+	}
+	if t := c.TypeOrDefault(); t != "jar" {
+		id += "-" + t
+	}
+	return "SPDXRef-Package-" + spdxIdInvalidChars.ReplaceAllString(id, "-")
+}
+
+// spdxLicenseRefs assigns LicenseRef ids to the licenses without SPDX id of
+// a document: the same license always gets the same id, different licenses
+// whose names sanitize alike get numbered ids.
+type spdxLicenseRefs struct {
+	ids   map[string]string
+	taken map[string]bool
+}
+
+// ref returns the extracted license info of l, and whether it is the first
+// time l is seen.
+func (r *spdxLicenseRefs) ref(l License) (SPDXExtractedLicense, bool) {
+	name, url := strings.TrimSpace(l.Name), strings.TrimSpace(l.URL)
+	label := name
+	if label == "" {
+		label = url
+	}
+	info := SPDXExtractedLicense{ExtractedText: label, Name: label}
+	if label == "" {
+		info.ExtractedText, info.Name = spdxNoAssertion, spdxNoAssertion
+	}
+	if url != "" {
+		info.SeeAlsos = []string{url}
+	}
+
+	key := name + "\x00" + url
+	if id, ok := r.ids[key]; ok {
+		info.LicenseId = id
+		return info, false
+	}
+	if r.ids == nil {
+		r.ids, r.taken = map[string]string{}, map[string]bool{}
+	}
+	base := strings.Trim(spdxIdInvalidChars.ReplaceAllString(label, "-"), "-")
+	if base == "" {
+		base = "unnamed"
+	}
+	id := "LicenseRef-" + base
+	for i := 2; r.taken[id]; i++ {
+		id = fmt.Sprintf("LicenseRef-%s-%d", base, i)
+	}
+	r.ids[key], r.taken[id] = id, true
+	info.LicenseId = id
+	return info, true
+}
+
+func (d *SPDXDocument) ToJSON() ([]byte, error) {
+	return json.MarshalIndent(d, "", "  ")
+}
+
+// WriteTagValue writes the document in the SPDX tag-value format.
+func (d *SPDXDocument) WriteTagValue(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	tag := func(name, value string) {
+		if value != "" {
+			fmt.Fprintf(bw, "%s: %s\n", name, value)
+		}
+	}
+	tag("SPDXVersion", d.SPDXVersion)
+	tag("DataLicense", d.DataLicense)
+	tag("SPDXID", d.SPDXID)
+	tag("DocumentName", d.Name)
+	tag("DocumentNamespace", d.DocumentNamespace)
+	for _, creator := range d.CreationInfo.Creators {
+		tag("Creator", creator)
+	}
+	tag("Created", d.CreationInfo.Created)
+
+	for _, p := range d.Packages {
+		fmt.Fprintln(bw)
+		tag("PackageName", p.Name)
+		tag("SPDXID", p.SPDXID)
+		tag("PackageVersion", p.VersionInfo)
+		tag("PackageSupplier", p.Supplier)
+		tag("PackageDownloadLocation", p.DownloadLocation)
+		tag("FilesAnalyzed", fmt.Sprint(p.FilesAnalyzed))
+		for _, c := range p.Checksums {
+			tag("PackageChecksum", c.Algorithm+": "+c.ChecksumValue)
+		}
+		tag("PackageHomePage", p.HomePage)
+		tag("PackageLicenseConcluded", p.LicenseConcluded)
+		tag("PackageLicenseDeclared", p.LicenseDeclared)
+		tag("PackageCopyrightText", p.CopyrightText)
+		if p.Description != "" {
+			tag("PackageDescription", "<text>"+p.Description+"</text>")
+		}
+		for _, ref := range p.ExternalRefs {
+			tag("ExternalRef", ref.ReferenceCategory+" "+ref.ReferenceType+" "+ref.ReferenceLocator)
+		}
+	}
+
+	if len(d.Relationships) > 0 {
+		fmt.Fprintln(bw)
+	}
+	for _, r := range d.Relationships {
+		tag("Relationship", r.SPDXElementId+" "+r.RelationshipType+" "+r.RelatedSPDXElement)
+	}
+
+	for _, l := range d.HasExtractedLicensingInfos {
+		fmt.Fprintln(bw)
+		tag("LicenseID", l.LicenseId)
+		tag("ExtractedText", "<text>"+l.ExtractedText+"</text>")
+		tag("LicenseName", l.Name)
+		for _, url := range l.SeeAlsos {
+			tag("LicenseCrossReference", url)
+		}
+	}
+	return bw.Flush()
+}
+
+func (d *SPDXDocument) ToTagValue() (string, error) {
+	var buf bytes.Buffer
+	err := d.WriteTagValue(&buf)
+	if err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
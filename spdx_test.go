@@ -0,0 +1,94 @@
+package mvnparse
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSPDX_ExtractedLicenses(t *testing.T) {
+	root := testGraph()
+	root.Project = &Project{Licenses: &[]License{
+		{Name: "Acme Commercial License", URL: "https://acme.example.com/license"},
+		{Name: "CDDL + GPLv2 with classpath exception"},
+	}}
+	doc, err := NewSPDX(root, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "LicenseRef-Acme-Commercial-License OR (CDDL-1.1 OR GPL-2.0-only WITH Classpath-exception-2.0)", doc.Packages[0].LicenseDeclared)
+	assert.Equal(t, []SPDXExtractedLicense{{
+		LicenseId:     "LicenseRef-Acme-Commercial-License",
+		ExtractedText: "Acme Commercial License",
+		Name:          "Acme Commercial License",
+		SeeAlsos:      []string{"https://acme.example.com/license"},
+	}}, doc.HasExtractedLicensingInfos)
+
+	tv, err := doc.ToTagValue()
+	assert.NoError(t, err)
+	assert.Contains(t, tv, "LicenseID: LicenseRef-Acme-Commercial-License\n")
+	assert.Contains(t, tv, "LicenseCrossReference: https://acme.example.com/license\n")
+}
+
+func TestNewSPDX_ExtractedLicenseIds(t *testing.T) {
+	root := testGraph()
+	root.Project = &Project{Licenses: &[]License{
+		{Name: "Acme License"},
+		{Name: "Acme/License"},
+		{},
+		{Name: "Acme License"},
+	}}
+	// lib-a uses the same license as the root and another unnamed one
+	root.Children[0].Project = &Project{Licenses: &[]License{{Name: "Acme License"}, {URL: "https://acme.example.com"}}}
+	doc, err := NewSPDX(root, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "LicenseRef-Acme-License OR LicenseRef-Acme-License-2 OR LicenseRef-unnamed OR LicenseRef-Acme-License", doc.Packages[0].LicenseDeclared)
+	assert.Equal(t, "LicenseRef-Acme-License OR LicenseRef-https-acme.example.com", doc.Packages[1].LicenseDeclared)
+	assert.Len(t, doc.HasExtractedLicensingInfos, 4)
+	assert.Equal(t, SPDXExtractedLicense{LicenseId: "LicenseRef-unnamed", ExtractedText: "NOASSERTION", Name: "NOASSERTION"}, doc.HasExtractedLicensingInfos[2])
+}
+
+func TestNewSPDX(t *testing.T) {
+	dir, repo := writeTestRepository(t)
+	defer os.RemoveAll(dir)
+	guava := Coordinates{GroupId: "com.google.guava", ArtifactId: "guava", Version: "31.1-jre"}
+	assert.NoError(t, ioutil.WriteFile(repo.PomPath(guava), []byte(testGuavaPom), 0644))
+
+	root := testGraph()
+	root.Project = testRootProject()
+	doc, err := NewSPDX(root, repo)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "SPDX-2.3", doc.SPDXVersion)
+	assert.Len(t, doc.Packages, 7)
+	assert.Equal(t, "SPDXRef-Package-com.example-app-1.0", doc.Packages[0].SPDXID)
+	assert.Equal(t, "Organization: Example Corp", doc.Packages[0].Supplier)
+
+	pkg := doc.Packages[2]
+	assert.Equal(t, "Guava: Google Core Libraries for Java", pkg.Name)
+	assert.Equal(t, "Apache-2.0", pkg.LicenseDeclared)
+	assert.Equal(t, "NOASSERTION", pkg.LicenseConcluded)
+	assert.Equal(t, "SHA1", pkg.Checksums[1].Algorithm)
+	assert.Equal(t, "pkg:maven/com.google.guava/guava@31.1-jre", pkg.ExternalRefs[0].ReferenceLocator)
+	assert.Equal(t, "NOASSERTION", doc.Packages[3].LicenseDeclared)
+
+	assert.Contains(t, doc.Relationships, SPDXRelationship{"SPDXRef-DOCUMENT", "DESCRIBES", "SPDXRef-Package-com.example-app-1.0"})
+	assert.Contains(t, doc.Relationships, SPDXRelationship{"SPDXRef-Package-com.example-lib-a-1.0", "DEPENDS_ON", "SPDXRef-Package-com.google.guava-guava-31.1-jre"})
+	assert.Contains(t, doc.Relationships, SPDXRelationship{"SPDXRef-Package-junit-junit-4.13.2", "DEV_DEPENDENCY_OF", "SPDXRef-Package-com.example-app-1.0"})
+	assert.Len(t, doc.HasExtractedLicensingInfos, 0)
+
+	data, err := doc.ToJSON()
+	assert.NoError(t, err)
+	var parsed map[string]interface{}
+	assert.NoError(t, json.Unmarshal(data, &parsed))
+	assert.Equal(t, "SPDXRef-DOCUMENT", parsed["SPDXID"])
+
+	tv, err := doc.ToTagValue()
+	assert.NoError(t, err)
+	assert.Contains(t, tv, "SPDXVersion: SPDX-2.3\nDataLicense: CC0-1.0\n")
+	assert.Contains(t, tv, "PackageName: Guava: Google Core Libraries for Java\nSPDXID: SPDXRef-Package-com.google.guava-guava-31.1-jre\nPackageVersion: 31.1-jre\n")
+	assert.Contains(t, tv, "ExternalRef: PACKAGE-MANAGER purl pkg:maven/com.google.guava/guava@31.1-jre\n")
+	assert.Contains(t, tv, "Relationship: SPDXRef-Package-junit-junit-4.13.2 DEV_DEPENDENCY_OF SPDXRef-Package-com.example-app-1.0\n")
+	assert.Contains(t, tv, "PackageLicenseDeclared: Apache-2.0\n")
+}
@@ -0,0 +1,144 @@
+package mvnparse
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+// WriteTree writes the graph rooted at n in the format of mvn
+// dependency:tree. In verbose mode omitted nodes are kept and annotated, as
+// are managed versions and scopes.
+func (n *DependencyNode) WriteTree(w io.Writer, verbose bool) error {
+	_, err := io.WriteString(w, n.ArtifactString(true)+"\n")
+	if err != nil {
+		return err
+	}
+	return writeTreeChildren(w, n.Children, "", verbose)
+}
+
+func writeTreeChildren(w io.Writer, children []*DependencyNode, indent string, verbose bool) error {
+	visible := children
+	if !verbose {
+		visible = make([]*DependencyNode, 0, len(children))
+		for _, child := range children {
+			if child.Included() {
+				visible = append(visible, child)
+			}
+		}
+	}
+	for i, child := range visible {
+		last := i == len(visible)-1
+		branch, next := "+- ", "|  "
+		if last {
+			branch, next = "\\- ", "   "
+		}
+		_, err := io.WriteString(w, indent+branch+child.nodeString(verbose)+"\n")
+		if err != nil {
+			return err
+		}
+		err = writeTreeChildren(w, child.Children, indent+next, verbose)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TreeStr returns the output of WriteTree as a string.
+func (n *DependencyNode) TreeStr(verbose bool) string {
+	var buf bytes.Buffer
+	_ = n.WriteTree(&buf, verbose)
+	return buf.String()
+}
+
+func (n *DependencyNode) nodeString(verbose bool) string {
+	var b strings.Builder
+	included := n.Included()
+	if !included {
+		b.WriteString("(")
+	}
+	b.WriteString(n.ArtifactString(false))
+	if n.Optional() {
+		b.WriteString(" (optional)")
+	}
+
+	if verbose {
+		var items []string
+		if n.PremanagedVersion != "" {
+			items = append(items, "version managed from "+n.PremanagedVersion)
+		}
+		if n.PremanagedScope != "" {
+			items = append(items, "scope managed from "+n.PremanagedScope)
+		}
+		if n.OriginalScope != "" {
+			items = append(items, "scope updated from "+n.OriginalScope)
+		}
+		switch n.State {
+		case NodeOmittedForDuplicate:
+			items = append(items, "omitted for duplicate")
+		case NodeOmittedForConflict:
+			related := ""
+			if n.Related != nil {
+				related = n.Related.Dependency.Version
+			}
+			items = append(items, "omitted for conflict with "+related)
+		case NodeOmittedForCycle:
+			items = append(items, "omitted for cycle")
+		}
+		if len(items) > 0 {
+			if included {
+				b.WriteString(" (" + strings.Join(items, "; ") + ")")
+			} else {
+				b.WriteString(" - " + strings.Join(items, "; "))
+			}
+		}
+	}
+
+	if !included {
+		b.WriteString(")")
+	}
+	return b.String()
+}
+
+// jsonNode mirrors the JSON output type of dependency:tree.
+type jsonNode struct {
+	GroupId    string      `json:"groupId"`
+	ArtifactId string      `json:"artifactId"`
+	Version    string      `json:"version"`
+	Type       string      `json:"type"`
+	Scope      string      `json:"scope"`
+	Classifier string      `json:"classifier"`
+	Optional   string      `json:"optional"`
+	Children   []*jsonNode `json:"children,omitempty"`
+}
+
+func (n *DependencyNode) toJSONNode(root bool) *jsonNode {
+	node := &jsonNode{
+		GroupId:    n.Dependency.GroupId,
+		ArtifactId: n.Dependency.ArtifactId,
+		Version:    n.Dependency.Version,
+		Type:       n.Coordinates().TypeOrDefault(),
+		Classifier: n.Dependency.Classifier,
+		Optional:   "false",
+	}
+	if !root {
+		node.Scope = n.Scope()
+	}
+	if n.Optional() {
+		node.Optional = "true"
+	}
+	for _, child := range n.Children {
+		if child.Included() {
+			node.Children = append(node.Children, child.toJSONNode(false))
+		}
+	}
+	return node
+}
+
+// TreeJSON returns the included nodes of the graph rooted at n in the JSON
+// format of dependency:tree -DoutputType=json.
+func (n *DependencyNode) TreeJSON() ([]byte, error) {
+	return json.MarshalIndent(n.toJSONNode(true), "", "  ")
+}
@@ -0,0 +1,48 @@
+package mvnparse
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDependencyNode_TreeStr(t *testing.T) {
+	expected := `com.example:app:jar:1.0
++- com.example:lib-a:jar:1.0:compile
+|  +- com.google.guava:guava:jar:31.1-jre:compile
+|  \- org.slf4j:slf4j-api:jar:2.0.9:compile
++- com.example:lib-b:jar:2.0:runtime
+\- junit:junit:jar:4.13.2:test
+   \- org.hamcrest:hamcrest-core:jar:1.3:test
+`
+	assert.Equal(t, expected, testGraph().TreeStr(false))
+
+	expected = `com.example:app:jar:1.0
++- com.example:lib-a:jar:1.0:compile
+|  +- com.google.guava:guava:jar:31.1-jre:compile (version managed from 31.0-jre)
+|  \- org.slf4j:slf4j-api:jar:2.0.9:compile
++- com.example:lib-b:jar:2.0:runtime
+|  +- (com.google.guava:guava:jar:30.0-jre:compile - omitted for conflict with 31.1-jre)
+|  \- (org.slf4j:slf4j-api:jar:2.0.9:compile - omitted for duplicate)
+\- junit:junit:jar:4.13.2:test
+   \- org.hamcrest:hamcrest-core:jar:1.3:test
+`
+	assert.Equal(t, expected, testGraph().TreeStr(true))
+}
+
+func TestDependencyNode_TreeJSON(t *testing.T) {
+	data, err := testGraph().TreeJSON()
+	assert.NoError(t, err)
+
+	var root map[string]interface{}
+	assert.NoError(t, json.Unmarshal(data, &root))
+	assert.Equal(t, "app", root["artifactId"])
+	assert.Equal(t, "", root["scope"])
+	children := root["children"].([]interface{})
+	assert.Len(t, children, 3)
+	libB := children[1].(map[string]interface{})
+	assert.Equal(t, "runtime", libB["scope"])
+	assert.Equal(t, "false", libB["optional"])
+	assert.Nil(t, libB["children"])
+}
@@ -0,0 +1,398 @@
+package mvnparse
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var ErrMetadataNotFound = errors.New("metadata not found")
+
+// MetadataSource provides the artifact level maven-metadata.xml listing the
+// versions of groupId:artifactId. It returns ErrMetadataNotFound for unknown
+// artifacts.
+type MetadataSource interface {
+	Metadata(groupId, artifactId string) (*Metadata, error)
+}
+
+// Metadata merges the maven-metadata-*.xml files of the artifact directory,
+// as cached from every remote repository and written by local installs.
+func (r *LocalRepository) Metadata(groupId, artifactId string) (*Metadata, error) {
+	files, err := filepath.Glob(filepath.Join(r.versionDir(groupId, artifactId, ""), "maven-metadata-*.xml"))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, ErrMetadataNotFound
+	}
+	sort.Strings(files)
+	merged := &Metadata{GroupId: groupId, ArtifactId: artifactId, Versioning: &Versioning{Versions: &[]string{}}}
+	for _, f := range files {
+		m, err := ParseMetadata(f)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", f, err)
+		}
+		mergeMetadata(merged, m)
+	}
+	return merged, nil
+}
+
+func mergeMetadata(dst, src *Metadata) {
+	if src.Versioning == nil {
+		return
+	}
+	v := dst.Versioning
+	if src.Versioning.Versions != nil {
+		for _, version := range *src.Versioning.Versions {
+			if !containsString(*v.Versions, version) {
+				*v.Versions = append(*v.Versions, version)
+			}
+		}
+		sort.SliceStable(*v.Versions, func(i, j int) bool {
+			return CompareVersions((*v.Versions)[i], (*v.Versions)[j]) < 0
+		})
+	}
+	if v.Latest == "" || CompareVersions(src.Versioning.Latest, v.Latest) > 0 {
+		v.Latest = src.Versioning.Latest
+	}
+	if v.Release == "" || CompareVersions(src.Versioning.Release, v.Release) > 0 {
+		v.Release = src.Versioning.Release
+	}
+	if src.Versioning.LastUpdated > v.LastUpdated {
+		v.LastUpdated = src.Versioning.LastUpdated
+	}
+}
+
+// RemoteRepository reads metadata from a repository over HTTP, such as
+// https://repo.maven.apache.org/maven2.
+type RemoteRepository struct {
+	URL string
+	// Client is http.DefaultClient when nil.
+	Client *http.Client
+}
+
+func NewRemoteRepository(url string) *RemoteRepository {
+	return &RemoteRepository{URL: strings.TrimSuffix(url, "/")}
+}
+
+func (r *RemoteRepository) Metadata(groupId, artifactId string) (*Metadata, error) {
+	client := r.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	url := strings.Join([]string{strings.TrimSuffix(r.URL, "/"), strings.Replace(groupId, ".", "/", -1), artifactId, "maven-metadata.xml"}, "/")
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrMetadataNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	var m Metadata
+	if err := xml.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("%s: %v", url, err)
+	}
+	return &m, nil
+}
+
+type UpdateSegment string
+
+const (
+	UpdateIncremental UpdateSegment = "incremental"
+	UpdateMinor       UpdateSegment = "minor"
+	UpdateMajor       UpdateSegment = "major"
+)
+
+// UpdateRules select the versions proposed as updates.
+type UpdateRules struct {
+	// IgnoreQualifiers skips versions with these qualifiers, such as alpha,
+	// beta, milestone, rc or snapshot. Aliases are understood both ways, so
+	// rc also ignores 1.0-cr1 and milestone or M ignore 1.0-M1.
+	IgnoreQualifiers []string     `json:"ignoreQualifiers,omitempty"`
+	Rules            []UpdateRule `json:"rules,omitempty"`
+}
+
+// UpdateRule restricts the updates of the artifacts matching Artifact.
+type UpdateRule struct {
+	// Artifact is a pattern groupId[:artifactId] with * wildcards.
+	Artifact string `json:"artifact"`
+	// IgnoreVersions are regular expressions matching whole versions.
+	IgnoreVersions   []string `json:"ignoreVersions,omitempty"`
+	IgnoreQualifiers []string `json:"ignoreQualifiers,omitempty"`
+	// MaxSegment stops proposing updates above this segment, e.g. minor
+	// only proposes incremental and minor updates.
+	MaxSegment UpdateSegment `json:"maxSegment,omitempty"`
+}
+
+type UpdateKind string
+
+const (
+	UpdateDependency        UpdateKind = "dependency"
+	UpdateManagedDependency UpdateKind = "managed dependency"
+	UpdatePlugin            UpdateKind = "plugin"
+	UpdateManagedPlugin     UpdateKind = "managed plugin"
+	UpdateExtension         UpdateKind = "extension"
+	UpdateParent            UpdateKind = "parent"
+)
+
+// ArtifactUpdate lists the newest version of each segment newer than the
+// current one, empty when there is none.
+type ArtifactUpdate struct {
+	Kind        UpdateKind
+	Coordinates Coordinates
+	// Property is the property defining the version, if any.
+	Property    string
+	Incremental string
+	Minor       string
+	Major       string
+}
+
+// Latest returns the newest version proposed.
+func (u ArtifactUpdate) Latest() string {
+	for _, v := range []string{u.Major, u.Minor, u.Incremental} {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+type UpdateReport struct {
+	Updates []ArtifactUpdate
+}
+
+// Updates looks up newer versions of the parent, dependencies, managed
+// dependencies, plugins, managed plugins and extensions of p. Versions
+// are interpolated from the properties of p; artifacts without version, with
+// unresolved properties or unknown to source are skipped. rules may be nil.
+func (p *Project) Updates(source MetadataSource, rules *UpdateRules) (*UpdateReport, error) {
+	if rules == nil {
+		rules = &UpdateRules{}
+	}
+	type candidate struct {
+		kind    UpdateKind
+		c       Coordinates
+		version string
+	}
+	var candidates []candidate
+	add := func(kind UpdateKind, c Coordinates) {
+		candidates = append(candidates, candidate{kind, c, c.Version})
+	}
+	if p.Parent != nil {
+		add(UpdateParent, p.Parent.Coordinates())
+	}
+	if p.Dependencies != nil {
+		for _, d := range *p.Dependencies {
+			add(UpdateDependency, d.Coordinates())
+		}
+	}
+	if p.DependencyManagement != nil && p.DependencyManagement.Dependencies != nil {
+		for _, d := range *p.DependencyManagement.Dependencies {
+			add(UpdateManagedDependency, d.Coordinates())
+		}
+	}
+	if b := p.Build; b != nil {
+		if b.Plugins != nil {
+			for _, plugin := range *b.Plugins {
+				add(UpdatePlugin, plugin.Coordinates())
+			}
+		}
+		if b.PluginManagement != nil {
+			for _, plugin := range b.PluginManagement.Plugins {
+				add(UpdateManagedPlugin, plugin.Coordinates())
+			}
+		}
+		if b.Extensions != nil {
+			for _, e := range *b.Extensions {
+				add(UpdateExtension, e.Coordinates())
+			}
+		}
+	}
+
+	report := &UpdateReport{}
+	cache := map[string]*Metadata{}
+	for _, cand := range candidates {
+		c := cand.c
+		c.GroupId = p.Interpolate(c.GroupId)
+		c.ArtifactId = p.Interpolate(c.ArtifactId)
+		c.Version = p.Interpolate(c.Version)
+		if c.Version == "" || strings.Contains(c.Version, "${") || strings.Contains(c.GroupId+c.ArtifactId, "${") {
+			continue
+		}
+		key := c.GroupId + ":" + c.ArtifactId
+		m, ok := cache[key]
+		if !ok {
+			var err error
+			m, err = source.Metadata(c.GroupId, c.ArtifactId)
+			if err != nil && err != ErrMetadataNotFound {
+				return nil, fmt.Errorf("%s: %v", key, err)
+			}
+			cache[key] = m
+		}
+		if m == nil || m.Versioning == nil || m.Versioning.Versions == nil {
+			continue
+		}
+		u, err := rules.update(c, *m.Versioning.Versions)
+		if err != nil {
+			return nil, err
+		}
+		if u.Latest() == "" {
+			continue
+		}
+		u.Kind = cand.kind
+		u.Property, _ = propertyName(cand.version)
+		report.Updates = append(report.Updates, u)
+	}
+	return report, nil
+}
+
+func (r *UpdateRules) update(c Coordinates, versions []string) (ArtifactUpdate, error) {
+	u := ArtifactUpdate{Coordinates: c}
+	ignoreQualifiers := append([]string{}, r.IgnoreQualifiers...)
+	var ignoreVersions []*regexp.Regexp
+	maxSegment := UpdateMajor
+	for _, rule := range r.Rules {
+		if !matchArtifact(rule.Artifact, Dependency{GroupId: c.GroupId, ArtifactId: c.ArtifactId}) {
+			continue
+		}
+		ignoreQualifiers = append(ignoreQualifiers, rule.IgnoreQualifiers...)
+		for _, expr := range rule.IgnoreVersions {
+			re, err := regexp.Compile("^(?:" + expr + ")$")
+			if err != nil {
+				return u, fmt.Errorf("rule %s: %v", rule.Artifact, err)
+			}
+			ignoreVersions = append(ignoreVersions, re)
+		}
+		if rule.MaxSegment != "" {
+			maxSegment = rule.MaxSegment
+		}
+	}
+
+	current := ParseVersion(c.Version)
+	major, minor, _ := versionSegments(c.Version)
+	for _, version := range versions {
+		v := ParseVersion(version)
+		if v.Compare(current) <= 0 || hasAnyQualifier(version, ignoreQualifiers) {
+			continue
+		}
+		ignored := false
+		for _, re := range ignoreVersions {
+			ignored = ignored || re.MatchString(version)
+		}
+		if ignored {
+			continue
+		}
+		vMajor, vMinor, _ := versionSegments(version)
+		var newest *string
+		switch {
+		case vMajor != major:
+			if maxSegment != UpdateMajor {
+				continue
+			}
+			newest = &u.Major
+		case vMinor != minor:
+			if maxSegment == UpdateIncremental {
+				continue
+			}
+			newest = &u.Minor
+		default:
+			newest = &u.Incremental
+		}
+		if *newest == "" || v.Compare(ParseVersion(*newest)) > 0 {
+			*newest = version
+		}
+	}
+	return u, nil
+}
+
+var versionNumbers = regexp.MustCompile(`^(\d+)(?:\.(\d+))?(?:\.(\d+))?`)
+
+// versionSegments returns the leading major, minor and incremental numbers
+// of version, zero when missing.
+func versionSegments(version string) (major, minor, incremental int64) {
+	m := versionNumbers.FindStringSubmatch(version)
+	if m == nil {
+		return 0, 0, 0
+	}
+	segments := make([]int64, 3)
+	for i := range segments {
+		segments[i], _ = strconv.ParseInt(m[i+1], 10, 64)
+	}
+	return segments[0], segments[1], segments[2]
+}
+
+// hasAnyQualifier reports whether version has one of the qualifiers,
+// compared the way ParseVersion orders them, so that M, m and milestone or
+// cr and rc are the same.
+func hasAnyQualifier(version string, qualifiers []string) bool {
+	if len(qualifiers) == 0 {
+		return false
+	}
+	var found []string
+	for _, name := range versionQualifierNames(ParseVersion(version).list(), nil) {
+		found = append(found, comparableQualifier(name))
+	}
+	if IsSnapshot(version) {
+		found = append(found, comparableQualifier("snapshot"))
+	}
+	for _, q := range qualifiers {
+		// a lone letter is the alias it is in 1-m1
+		name := parseVersionItem(strings.ToLower(strings.TrimSpace(q)), false, true).(versionString)
+		if name != "" && containsString(found, comparableQualifier(string(name))) {
+			return true
+		}
+	}
+	return false
+}
+
+func versionQualifierNames(l *versionList, names []string) []string {
+	for _, item := range l.items {
+		switch v := item.(type) {
+		case versionString:
+			names = append(names, string(v))
+		case *versionList:
+			names = versionQualifierNames(v, names)
+		}
+	}
+	return names
+}
+
+// Text lists the updates of each artifact, the way
+// versions:display-dependency-updates does.
+func (r *UpdateReport) Text() string {
+	var buf bytes.Buffer
+	for _, u := range r.Updates {
+		c := u.Coordinates
+		fmt.Fprintf(&buf, "%s:%s:%s (%s)", c.GroupId, c.ArtifactId, c.Version, u.Kind)
+		if u.Property != "" {
+			fmt.Fprintf(&buf, " via ${%s}", u.Property)
+		}
+		var updates []string
+		for _, s := range []struct {
+			segment UpdateSegment
+			version string
+		}{{UpdateIncremental, u.Incremental}, {UpdateMinor, u.Minor}, {UpdateMajor, u.Major}} {
+			if s.version != "" {
+				updates = append(updates, fmt.Sprintf("%s %s", s.segment, s.version))
+			}
+		}
+		fmt.Fprintf(&buf, ": %s\n", strings.Join(updates, ", "))
+	}
+	return buf.String()
+}
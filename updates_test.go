@@ -0,0 +1,179 @@
+package mvnparse
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testUpdatesPom = `<project>
+  <parent>
+    <groupId>org.springframework.boot</groupId>
+    <artifactId>spring-boot-starter-parent</artifactId>
+    <version>3.1.0</version>
+  </parent>
+  <artifactId>app</artifactId>
+  <properties>
+    <guava.version>31.0-jre</guava.version>
+  </properties>
+  <dependencies>
+    <dependency>
+      <groupId>com.google.guava</groupId>
+      <artifactId>guava</artifactId>
+      <version>${guava.version}</version>
+    </dependency>
+    <dependency>
+      <groupId>junit</groupId>
+      <artifactId>junit</artifactId>
+    </dependency>
+    <dependency>
+      <groupId>com.example</groupId>
+      <artifactId>unknown</artifactId>
+      <version>1.0</version>
+    </dependency>
+  </dependencies>
+  <dependencyManagement>
+    <dependencies>
+      <dependency>
+        <groupId>junit</groupId>
+        <artifactId>junit</artifactId>
+        <version>4.12</version>
+      </dependency>
+    </dependencies>
+  </dependencyManagement>
+  <build>
+    <plugins>
+      <plugin>
+        <artifactId>maven-compiler-plugin</artifactId>
+        <version>3.11.0</version>
+      </plugin>
+    </plugins>
+  </build>
+</project>`
+
+func testMetadata(groupId, artifactId string, versions ...string) string {
+	return fmt.Sprintf(`<metadata>
+  <groupId>%s</groupId>
+  <artifactId>%s</artifactId>
+  <versioning>
+    <latest>%s</latest>
+    <versions>
+      <version>%s</version>
+    </versions>
+  </versioning>
+</metadata>`, groupId, artifactId, versions[len(versions)-1], strings.Join(versions, "</version>\n      <version>"))
+}
+
+func writeTestMetadata(t *testing.T) (string, *LocalRepository) {
+	dir, err := ioutil.TempDir("", "updates")
+	assert.NoError(t, err)
+	repo := NewLocalRepository(dir)
+	for path, content := range map[string]string{
+		repo.MetadataPath("com.google.guava", "guava", "", "central"):                       testMetadata("com.google.guava", "guava", "31.0-jre", "31.0.1-jre", "31.1-jre", "32.0.0-rc1", "32.1.3-jre"),
+		repo.MetadataPath("com.google.guava", "guava", "", ""):                              testMetadata("com.google.guava", "guava", "31.0-jre", "33.0-SNAPSHOT"),
+		repo.MetadataPath("junit", "junit", "", "central"):                                  testMetadata("junit", "junit", "4.12", "4.13-beta-1", "4.13.2", "5.0-M1"),
+		repo.MetadataPath("org.springframework.boot", "spring-boot-starter-parent", "", ""): testMetadata("org.springframework.boot", "spring-boot-starter-parent", "3.1.0", "3.1.5", "3.2.0"),
+		repo.MetadataPath("org.apache.maven.plugins", "maven-compiler-plugin", "", ""):      testMetadata("org.apache.maven.plugins", "maven-compiler-plugin", "3.11.0"),
+	} {
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+	}
+	return dir, repo
+}
+
+func TestLocalRepository_Metadata(t *testing.T) {
+	dir, repo := writeTestMetadata(t)
+	defer os.RemoveAll(dir)
+	m, err := repo.Metadata("com.google.guava", "guava")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"31.0-jre", "31.0.1-jre", "31.1-jre", "32.0.0-rc1", "32.1.3-jre", "33.0-SNAPSHOT"}, *m.Versioning.Versions)
+	assert.Equal(t, "33.0-SNAPSHOT", m.Versioning.Latest)
+	_, err = repo.Metadata("com.example", "unknown")
+	assert.Equal(t, ErrMetadataNotFound, err)
+}
+
+func TestProject_Updates(t *testing.T) {
+	dir, repo := writeTestMetadata(t)
+	defer os.RemoveAll(dir)
+	p, err := ParseStr(testUpdatesPom)
+	assert.NoError(t, err)
+
+	report, err := p.Updates(repo, nil)
+	assert.NoError(t, err)
+	assert.Len(t, report.Updates, 3)
+	assert.Equal(t, ArtifactUpdate{
+		Kind:        UpdateDependency,
+		Coordinates: Coordinates{GroupId: "com.google.guava", ArtifactId: "guava", Version: "31.0-jre"},
+		Property:    "guava.version",
+		Incremental: "31.0.1-jre",
+		Minor:       "31.1-jre",
+		Major:       "33.0-SNAPSHOT",
+	}, report.Updates[1])
+
+	report, err = p.Updates(repo, &UpdateRules{
+		IgnoreQualifiers: []string{"snapshot", "alpha", "beta", "milestone", "rc"},
+		Rules: []UpdateRule{
+			{Artifact: "junit", MaxSegment: UpdateMinor},
+			{Artifact: "org.springframework.boot:*", IgnoreVersions: []string{`3\.2\..*`}},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, `org.springframework.boot:spring-boot-starter-parent:3.1.0 (parent): incremental 3.1.5
+com.google.guava:guava:31.0-jre (dependency) via ${guava.version}: incremental 31.0.1-jre, minor 31.1-jre, major 32.1.3-jre
+junit:junit:4.12 (managed dependency): minor 4.13.2
+`, report.Text())
+
+	_, err = p.Updates(repo, &UpdateRules{Rules: []UpdateRule{{Artifact: "junit", IgnoreVersions: []string{"("}}}})
+	assert.Error(t, err)
+}
+
+func TestRemoteRepository_Metadata(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/maven2/com/google/guava/guava/maven-metadata.xml":
+			fmt.Fprint(w, testMetadata("com.google.guava", "guava", "31.0-jre", "31.1-jre"))
+		case "/maven2/com/example/broken/maven-metadata.xml":
+			http.Error(w, "boom", http.StatusInternalServerError)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	repo := NewRemoteRepository(server.URL + "/maven2/")
+	m, err := repo.Metadata("com.google.guava", "guava")
+	assert.NoError(t, err)
+	assert.Equal(t, "31.1-jre", m.Versioning.Latest)
+	_, err = repo.Metadata("com.example", "unknown")
+	assert.Equal(t, ErrMetadataNotFound, err)
+	_, err = repo.Metadata("com.example", "broken")
+	assert.Error(t, err)
+}
+
+func TestHasAnyQualifier(t *testing.T) {
+	for _, c := range []struct {
+		version    string
+		qualifiers []string
+		expected   bool
+	}{
+		{"1.0-M1", []string{"milestone"}, true},
+		{"1.0-M1", []string{"M"}, true},
+		{"1.0-milestone-2", []string{"m"}, true},
+		{"1.0-CR1", []string{"rc"}, true},
+		{"1.0-RC1", []string{"cr"}, true},
+		{"1.0a1", []string{"ALPHA"}, true},
+		{"1.0-SNAPSHOT", []string{"snapshot"}, true},
+		{"2.0", []string{"ga", "final"}, false},
+		{"31.1-jre", []string{"rc", "beta"}, false},
+		{"1.0-M1", nil, false},
+	} {
+		assert.Equal(t, c.expected, hasAnyQualifier(c.version, c.qualifiers), "%s %v", c.version, c.qualifiers)
+	}
+}
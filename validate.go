@@ -0,0 +1,294 @@
+package mvnparse
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Problem is an issue found in a POM. Path points at the offending element
+// as /project/dependencies/dependency[2]/version, indexes starting at 1.
+type Problem struct {
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+	Path     string   `json:"path"`
+	// Location is the position of the element at Path, or of its closest
+	// ancestor, when the POM was read from a file.
+	Location *Location `json:"location,omitempty"`
+}
+
+func (p Problem) String() string {
+	where := p.Path
+	if p.Location != nil {
+		where = p.Location.String()
+	}
+	return fmt.Sprintf("[%s] %s @ %s", strings.ToUpper(string(p.Severity)), p.Message, where)
+}
+
+// locateProblems sets the location of the problems from locations, when known.
+func locateProblems(problems []Problem, locations *Locations) []Problem {
+	for i := range problems {
+		if loc, ok := locations.Nearest(problems[i].Path); ok {
+			problems[i].Location = &loc
+		}
+	}
+	return problems
+}
+
+var (
+	validId = regexp.MustCompile(`^[A-Za-z0-9_\-.]+$`)
+	// ciFriendlyVersion are the only properties allowed in the project version.
+	ciFriendlyVersion = regexp.MustCompile(`\$\{(revision|sha1|changelist)\}`)
+)
+
+var validScopes = []string{ScopeProvided, ScopeCompile, ScopeRuntime, ScopeTest, ScopeSystem}
+
+type validator struct {
+	project  *Project
+	problems []Problem
+}
+
+func (v *validator) add(severity Severity, path, format string, args ...interface{}) {
+	v.problems = append(v.problems, Problem{Severity: severity, Message: fmt.Sprintf(format, args...), Path: path})
+}
+
+// Validate checks p the way Maven's model validator does on the raw model:
+// coordinates, aggregator packaging, parent, dependency and plugin
+// declarations in the project and its profiles. Values inherited from an
+// unknown parent are not reported missing.
+func (p *Project) Validate() []Problem {
+	v := &validator{project: p}
+	v.validateProject()
+	return v.problems
+}
+
+// Validate is Project.Validate with the problems located in the file.
+func (pf *ProjectFile) Validate() []Problem {
+	return locateProblems(pf.Project.Validate(), pf.Locations)
+}
+
+func (v *validator) validateProject() {
+	p := v.project
+	switch p.ModelVersion {
+	case "":
+		v.add(SeverityError, "/project/modelVersion", "'modelVersion' is missing.")
+	case "4.0.0":
+	default:
+		v.add(SeverityError, "/project/modelVersion", "'modelVersion' must be one of [4.0.0] but is '%s'.", p.ModelVersion)
+	}
+
+	if parent := p.Parent; parent != nil {
+		for _, f := range []struct{ name, value string }{{"groupId", parent.GroupId}, {"artifactId", parent.ArtifactId}, {"version", parent.Version}} {
+			if f.value == "" {
+				v.add(SeverityError, "/project/parent/"+f.name, "'parent.%s' is missing.", f.name)
+			}
+		}
+		c := p.Coordinates()
+		if c.GroupId == parent.GroupId && p.ArtifactId == parent.ArtifactId {
+			v.add(SeverityError, "/project/parent/artifactId", "The parent of %s:%s should not point at itself.", c.GroupId, p.ArtifactId)
+		}
+	}
+
+	c := p.Coordinates()
+	v.id("/project/groupId", "groupId", c.GroupId, p.GroupId != "")
+	v.id("/project/artifactId", "artifactId", p.ArtifactId, true)
+	if c.Version == "" {
+		v.add(SeverityError, "/project/version", "'version' is missing.")
+	} else if p.Version != "" && strings.Contains(ciFriendlyVersion.ReplaceAllString(p.Version, ""), "${") {
+		v.add(SeverityWarning, "/project/version", "'version' contains an expression but should be a constant.")
+	}
+
+	if p.Modules != nil && len(*p.Modules) > 0 && c.Type != "pom" {
+		v.add(SeverityError, "/project/packaging", "'packaging' with value '%s' is invalid. Aggregator projects require 'pom' as packaging.", c.Type)
+	}
+
+	v.dependencies("/project/dependencies/dependency", "dependencies.dependency", p.Dependencies, false)
+	if p.DependencyManagement != nil {
+		v.dependencies("/project/dependencyManagement/dependencies/dependency", "dependencyManagement.dependencies.dependency", p.DependencyManagement.Dependencies, true)
+	}
+	if p.Build != nil {
+		v.build("/project/build", "build", &p.Build.BuildBase, nil)
+	}
+	var projectManaged []Plugin
+	if p.Build != nil && p.Build.PluginManagement != nil {
+		projectManaged = p.Build.PluginManagement.Plugins
+	}
+
+	if p.Profiles != nil {
+		seen := map[string]bool{}
+		for i, profile := range *p.Profiles {
+			path := fmt.Sprintf("/project/profiles/profile[%d]", i+1)
+			if profile.Id == "" {
+				v.add(SeverityError, path+"/id", "'profiles.profile.id' is missing.")
+			} else if seen[profile.Id] {
+				v.add(SeverityError, path+"/id", "'profiles.profile.id' must be unique but found duplicate profile with id %s", profile.Id)
+			}
+			seen[profile.Id] = true
+			v.dependencies(path+"/dependencies/dependency", "profiles.profile[%s].dependencies.dependency", profile.Dependencies, false, profile.Id)
+			if profile.DependencyManagement != nil {
+				v.dependencies(path+"/dependencyManagement/dependencies/dependency", "profiles.profile[%s].dependencyManagement.dependencies.dependency", profile.DependencyManagement.Dependencies, true, profile.Id)
+			}
+			if profile.Build != nil {
+				v.build(path+"/build", fmt.Sprintf("profiles.profile[%s].build", profile.Id), profile.Build, projectManaged)
+			}
+		}
+	}
+}
+
+// id checks a groupId or artifactId, which may be inherited when
+// declared is false.
+func (v *validator) id(path, field, value string, declared bool) {
+	switch {
+	case value == "":
+		v.add(SeverityError, path, "'%s' is missing.", field)
+	case !declared || strings.Contains(value, "${"):
+	case !validId.MatchString(value):
+		v.add(SeverityError, path, "'%s' with value '%s' does not match a valid id pattern.", field, value)
+	}
+}
+
+// dependencies checks a dependencies section. field is the Maven name of the
+// section, possibly a format taking the profile id as argument.
+func (v *validator) dependencies(path, field string, deps *[]Dependency, managed bool, args ...interface{}) {
+	if deps == nil {
+		return
+	}
+	field = fmt.Sprintf(field, args...)
+	seen := map[string]string{}
+	for i, d := range *deps {
+		p := fmt.Sprintf("%s[%d]", path, i+1)
+		key := d.ManagementKey()
+		if d.GroupId == "" {
+			v.add(SeverityError, p+"/groupId", "'%s.groupId' for %s is missing.", field, key)
+		} else if !strings.Contains(d.GroupId, "${") && !validId.MatchString(d.GroupId) {
+			v.add(SeverityError, p+"/groupId", "'%s.groupId' for %s with value '%s' does not match a valid id pattern.", field, key, d.GroupId)
+		}
+		if d.ArtifactId == "" {
+			v.add(SeverityError, p+"/artifactId", "'%s.artifactId' for %s is missing.", field, key)
+		} else if !strings.Contains(d.ArtifactId, "${") && !validId.MatchString(d.ArtifactId) {
+			v.add(SeverityError, p+"/artifactId", "'%s.artifactId' for %s with value '%s' does not match a valid id pattern.", field, key, d.ArtifactId)
+		}
+
+		if previous, ok := seen[key]; ok {
+			v.add(SeverityWarning, p, "'%s.(groupId:artifactId:type:classifier)' must be unique: %s -> version %s vs %s", field, key, previous, d.Version)
+		}
+		seen[key] = d.Version
+
+		switch {
+		case d.Version == "":
+			if !managed && v.project.Parent == nil && !v.managed(key) {
+				v.add(SeverityError, p+"/version", "'%s.version' for %s is missing.", field, key)
+			}
+		case !validExpression(d.Version):
+			v.add(SeverityError, p+"/version", "'%s.version' for %s contains an invalid expression '%s'.", field, key, d.Version)
+		}
+
+		if d.Scope == ScopeImport {
+			if !managed {
+				v.add(SeverityWarning, p+"/scope", "'%s.scope' for %s declares usage of deprecated 'import' scope outside of dependency management.", field, key)
+			} else if d.Coordinates().TypeOrDefault() != "pom" {
+				v.add(SeverityError, p+"/type", "'%s.type' must be 'pom' if 'import' scope is used for %s", field, key)
+			}
+		} else if d.Scope != "" && !containsString(validScopes, d.Scope) && !strings.Contains(d.Scope, "${") {
+			v.add(SeverityWarning, p+"/scope", "'%s.scope' for %s must be one of [%s] but is '%s'.", field, key, strings.Join(validScopes, ", "), d.Scope)
+		}
+
+		switch {
+		case d.Scope == ScopeSystem && d.SystemPath == "":
+			v.add(SeverityError, p+"/systemPath", "'%s.systemPath' for %s is missing.", field, key)
+		case d.Scope != ScopeSystem && d.SystemPath != "" && (!managed || d.Scope != ""):
+			v.add(SeverityError, p+"/systemPath", "'%s.systemPath' for %s must be omitted. This field may only be specified for a dependency with system scope.", field, key)
+		}
+	}
+}
+
+// managed reports whether the dependency management of the project
+// declares a version for key.
+func (v *validator) managed(key string) bool {
+	m := v.project.DependencyManagement
+	if m == nil {
+		return false
+	}
+	i := findDependency(m.Dependencies, key)
+	return i >= 0 && (*m.Dependencies)[i].Version != ""
+}
+
+// build checks a build section, inherited listing the plugins managed by
+// the project build for the build of a profile.
+func (v *validator) build(path, field string, b *BuildBase, inherited []Plugin) {
+	managed := inherited
+	if b.PluginManagement != nil {
+		managed = append(append([]Plugin(nil), b.PluginManagement.Plugins...), inherited...)
+		v.plugins(path+"/pluginManagement/plugins/plugin", field+".pluginManagement.plugins.plugin", b.PluginManagement.Plugins, nil)
+	}
+	if b.Plugins != nil {
+		v.plugins(path+"/plugins/plugin", field+".plugins.plugin", *b.Plugins, managed)
+	}
+}
+
+func (v *validator) plugins(path, field string, plugins, managed []Plugin) {
+	seen := map[string]bool{}
+	for i, plugin := range plugins {
+		p := fmt.Sprintf("%s[%d]", path, i+1)
+		key := pluginKey(plugin)
+		if plugin.ArtifactId == "" {
+			v.add(SeverityError, p+"/artifactId", "'%s.artifactId' is missing.", field)
+		}
+		if seen[key] {
+			v.add(SeverityWarning, p, "'%s.(groupId:artifactId)' must be unique but found duplicate declaration of plugin %s", field, key)
+		}
+		seen[key] = true
+
+		switch {
+		case plugin.Version == "":
+			if v.project.Parent == nil {
+				versioned := false
+				for _, m := range managed {
+					versioned = versioned || (pluginKey(m) == key && m.Version != "")
+				}
+				if !versioned {
+					v.add(SeverityWarning, p+"/version", "'%s.version' for %s is missing.", field, key)
+				}
+			}
+		case !validExpression(plugin.Version):
+			v.add(SeverityError, p+"/version", "'%s.version' for %s contains an invalid expression '%s'.", field, key, plugin.Version)
+		}
+
+		if plugin.Executions != nil {
+			ids := map[string]bool{}
+			for j, e := range *plugin.Executions {
+				id := e.Id
+				if id == "" {
+					id = "default"
+				}
+				if ids[id] {
+					v.add(SeverityError, fmt.Sprintf("%s/executions/execution[%d]/id", p, j+1), "'%s.executions.execution.id' must be unique but found duplicate execution with id %s", field, id)
+				}
+				ids[id] = true
+			}
+		}
+	}
+}
+
+// validExpression reports whether every ${ of s is closed and names a
+// property, without stray braces.
+func validExpression(s string) bool {
+	for {
+		i := strings.Index(s, "${")
+		if i < 0 {
+			return !strings.ContainsAny(s, "{}")
+		}
+		end := strings.Index(s[i:], "}")
+		if end < 0 || end == 2 {
+			return false
+		}
+		s = s[i+end+1:]
+	}
+}
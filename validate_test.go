@@ -0,0 +1,161 @@
+package mvnparse
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProject_Validate(t *testing.T) {
+	p, err := ParseStr(`<project>
+  <groupId>com.example</groupId>
+  <artifactId>app!</artifactId>
+  <version>${app.version}</version>
+  <modules>
+    <module>core</module>
+  </modules>
+  <dependencies>
+    <dependency>
+      <groupId>junit</groupId>
+      <artifactId>junit</artifactId>
+      <version>4.13.2</version>
+    </dependency>
+    <dependency>
+      <groupId>junit</groupId>
+      <artifactId>junit</artifactId>
+      <version>4.13.1</version>
+      <scope>tests</scope>
+    </dependency>
+    <dependency>
+      <groupId>com.sun</groupId>
+      <artifactId>tools</artifactId>
+      <scope>system</scope>
+    </dependency>
+    <dependency>
+      <groupId>org.slf4j</groupId>
+      <artifactId>slf4j-api</artifactId>
+      <version>${slf4j.version</version>
+      <systemPath>/lib/slf4j.jar</systemPath>
+    </dependency>
+  </dependencies>
+  <dependencyManagement>
+    <dependencies>
+      <dependency>
+        <groupId>com.example</groupId>
+        <artifactId>bom</artifactId>
+        <version>1.0</version>
+        <scope>import</scope>
+      </dependency>
+    </dependencies>
+  </dependencyManagement>
+  <build>
+    <plugins>
+      <plugin>
+        <artifactId>maven-compiler-plugin</artifactId>
+        <executions>
+          <execution><id>a</id></execution>
+          <execution><id>a</id></execution>
+        </executions>
+      </plugin>
+      <plugin>
+        <artifactId>maven-compiler-plugin</artifactId>
+        <version>3.11.0</version>
+      </plugin>
+    </plugins>
+  </build>
+  <profiles>
+    <profile>
+      <id>ci</id>
+    </profile>
+    <profile>
+      <id>ci</id>
+    </profile>
+  </profiles>
+</project>`)
+	assert.NoError(t, err)
+
+	var lines []string
+	for _, problem := range p.Validate() {
+		lines = append(lines, problem.String())
+	}
+	assert.Equal(t, []string{
+		"[ERROR] 'modelVersion' is missing. @ /project/modelVersion",
+		"[ERROR] 'artifactId' with value 'app!' does not match a valid id pattern. @ /project/artifactId",
+		"[WARNING] 'version' contains an expression but should be a constant. @ /project/version",
+		"[ERROR] 'packaging' with value 'jar' is invalid. Aggregator projects require 'pom' as packaging. @ /project/packaging",
+		"[WARNING] 'dependencies.dependency.(groupId:artifactId:type:classifier)' must be unique: junit:junit:jar -> version 4.13.2 vs 4.13.1 @ /project/dependencies/dependency[2]",
+		"[WARNING] 'dependencies.dependency.scope' for junit:junit:jar must be one of [provided, compile, runtime, test, system] but is 'tests'. @ /project/dependencies/dependency[2]/scope",
+		"[ERROR] 'dependencies.dependency.version' for com.sun:tools:jar is missing. @ /project/dependencies/dependency[3]/version",
+		"[ERROR] 'dependencies.dependency.systemPath' for com.sun:tools:jar is missing. @ /project/dependencies/dependency[3]/systemPath",
+		"[ERROR] 'dependencies.dependency.version' for org.slf4j:slf4j-api:jar contains an invalid expression '${slf4j.version'. @ /project/dependencies/dependency[4]/version",
+		"[ERROR] 'dependencies.dependency.systemPath' for org.slf4j:slf4j-api:jar must be omitted. This field may only be specified for a dependency with system scope. @ /project/dependencies/dependency[4]/systemPath",
+		"[ERROR] 'dependencyManagement.dependencies.dependency.type' must be 'pom' if 'import' scope is used for com.example:bom:jar @ /project/dependencyManagement/dependencies/dependency[1]/type",
+		"[WARNING] 'build.plugins.plugin.version' for org.apache.maven.plugins:maven-compiler-plugin is missing. @ /project/build/plugins/plugin[1]/version",
+		"[ERROR] 'build.plugins.plugin.executions.execution.id' must be unique but found duplicate execution with id a @ /project/build/plugins/plugin[1]/executions/execution[2]/id",
+		"[WARNING] 'build.plugins.plugin.(groupId:artifactId)' must be unique but found duplicate declaration of plugin org.apache.maven.plugins:maven-compiler-plugin @ /project/build/plugins/plugin[2]",
+		"[ERROR] 'profiles.profile.id' must be unique but found duplicate profile with id ci @ /project/profiles/profile[2]/id",
+	}, lines)
+
+	for _, name := range []string{testRootPom, testCorePom, testAppPom} {
+		p, err := ParseStr(name)
+		assert.NoError(t, err)
+		assert.Empty(t, p.Validate())
+	}
+	p, err = ParseStr(`<project><modelVersion>4.0.0</modelVersion><parent><groupId>g</groupId><artifactId>a</artifactId></parent><artifactId>a</artifactId></project>`)
+	assert.NoError(t, err)
+	assert.Equal(t, []Problem{
+		{Severity: SeverityError, Message: "'parent.version' is missing.", Path: "/project/parent/version"},
+		{Severity: SeverityError, Message: "The parent of g:a should not point at itself.", Path: "/project/parent/artifactId"},
+		{Severity: SeverityError, Message: "'version' is missing.", Path: "/project/version"},
+	}, p.Validate())
+}
+
+func TestProject_Validate_ProfilePlugins(t *testing.T) {
+	p, err := ParseStr(`<project>
+  <modelVersion>4.0.0</modelVersion>
+  <groupId>com.example</groupId>
+  <artifactId>app</artifactId>
+  <version>1.0</version>
+  <build>
+    <pluginManagement>
+      <plugins>
+        <plugin>
+          <artifactId>maven-surefire-plugin</artifactId>
+          <version>3.2.5</version>
+        </plugin>
+      </plugins>
+    </pluginManagement>
+  </build>
+  <profiles>
+    <profile>
+      <id>it</id>
+      <build>
+        <pluginManagement>
+          <plugins>
+            <plugin>
+              <artifactId>maven-failsafe-plugin</artifactId>
+              <version>3.2.5</version>
+            </plugin>
+          </plugins>
+        </pluginManagement>
+        <plugins>
+          <plugin>
+            <artifactId>maven-surefire-plugin</artifactId>
+          </plugin>
+          <plugin>
+            <artifactId>maven-failsafe-plugin</artifactId>
+          </plugin>
+          <plugin>
+            <artifactId>maven-jar-plugin</artifactId>
+          </plugin>
+        </plugins>
+      </build>
+    </profile>
+  </profiles>
+</project>`)
+	assert.NoError(t, err)
+	// the plugins managed by the project build apply to the profile
+	assert.Equal(t, []Problem{
+		{Severity: SeverityWarning, Message: "'profiles.profile[it].build.plugins.plugin.version' for org.apache.maven.plugins:maven-jar-plugin is missing.", Path: "/project/profiles/profile[1]/build/plugins/plugin[3]/version"},
+	}, p.Validate())
+}
@@ -0,0 +1,413 @@
+package mvnparse
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is a version parsed with the ordering of Maven's
+// ComparableVersion: numeric segments compare as numbers and qualifiers
+// follow alpha < beta < milestone < rc < snapshot < release < sp, so
+// 1.0-alpha-1 < 1.0-SNAPSHOT < 1.0 = 1.0.0 = 1.0-ga < 1.0-sp < 1.0.1.
+type Version struct {
+	raw   string
+	items *versionList
+}
+
+// versionQualifiers are the well-known qualifiers in ascending order, the
+// empty one standing for a release.
+var versionQualifiers = []string{"alpha", "beta", "milestone", "rc", "snapshot", "", "sp"}
+
+var versionAliases = map[string]string{
+	"ga":      "",
+	"final":   "",
+	"release": "",
+	"cr":      "rc",
+}
+
+type versionItem interface {
+	// compare compares the item to other, which is nil past the end of the
+	// shorter version.
+	compare(other versionItem) int
+	isNull() bool
+}
+
+// versionInt holds the digits of a numeric segment without leading zeros,
+// so arbitrarily large numbers compare correctly.
+type versionInt string
+
+// versionString holds a qualifier with its aliases resolved.
+type versionString string
+
+type versionList struct {
+	items []versionItem
+}
+
+func (i versionInt) isNull() bool { return i == "" }
+
+func (i versionInt) compare(other versionItem) int {
+	switch o := other.(type) {
+	case nil:
+		if i.isNull() {
+			return 0
+		}
+		return 1
+	case versionInt:
+		if len(i) != len(o) {
+			return compareInts(len(i), len(o))
+		}
+		return strings.Compare(string(i), string(o))
+	default:
+		// 1.1 > 1-sp and 1.1 > 1-1
+		return 1
+	}
+}
+
+func (s versionString) isNull() bool { return s == "" }
+
+func (s versionString) compare(other versionItem) int {
+	switch o := other.(type) {
+	case nil:
+		// 1-rc < 1, 1-sp > 1
+		return strings.Compare(comparableQualifier(string(s)), comparableQualifier(""))
+	case versionString:
+		return strings.Compare(comparableQualifier(string(s)), comparableQualifier(string(o)))
+	default:
+		return -1
+	}
+}
+
+func comparableQualifier(q string) string {
+	for i, known := range versionQualifiers {
+		if q == known {
+			return strconv.Itoa(i)
+		}
+	}
+	// unknown qualifiers come after the known ones, in lexical order
+	return strconv.Itoa(len(versionQualifiers)) + "-" + q
+}
+
+func (l *versionList) isNull() bool { return len(l.items) == 0 }
+
+func (l *versionList) compare(other versionItem) int {
+	switch o := other.(type) {
+	case nil:
+		if len(l.items) == 0 {
+			return 0
+		}
+		return l.items[0].compare(nil)
+	case versionInt:
+		return -1
+	case versionString:
+		return 1
+	case *versionList:
+		for i := 0; i < len(l.items) || i < len(o.items); i++ {
+			var left, right versionItem
+			if i < len(l.items) {
+				left = l.items[i]
+			}
+			if i < len(o.items) {
+				right = o.items[i]
+			}
+			var result int
+			if left == nil {
+				if right != nil {
+					result = -right.compare(nil)
+				}
+			} else {
+				result = left.compare(right)
+			}
+			if result != 0 {
+				return result
+			}
+		}
+		return 0
+	}
+	return 0
+}
+
+// normalize drops trailing null items, so 1.0.0 equals 1.
+func (l *versionList) normalize() {
+	for i := len(l.items) - 1; i >= 0; i-- {
+		if l.items[i].isNull() {
+			l.items = append(l.items[:i], l.items[i+1:]...)
+		} else if _, ok := l.items[i].(*versionList); !ok {
+			break
+		}
+	}
+}
+
+func (l *versionList) String() string {
+	var buf strings.Builder
+	for i, item := range l.items {
+		if i > 0 {
+			if _, ok := item.(*versionList); ok {
+				buf.WriteByte('-')
+			} else {
+				buf.WriteByte('.')
+			}
+		}
+		switch v := item.(type) {
+		case versionInt:
+			if v == "" {
+				buf.WriteByte('0')
+			} else {
+				buf.WriteString(string(v))
+			}
+		case versionString:
+			buf.WriteString(string(v))
+		case *versionList:
+			buf.WriteString(v.String())
+		}
+	}
+	return buf.String()
+}
+
+func parseVersionItem(s string, isDigit, followedByDigit bool) versionItem {
+	if isDigit {
+		return versionInt(strings.TrimLeft(s, "0"))
+	}
+	if followedByDigit && len(s) == 1 {
+		// 1.0a1 is 1.0-alpha-1
+		switch s {
+		case "a":
+			s = "alpha"
+		case "b":
+			s = "beta"
+		case "m":
+			s = "milestone"
+		}
+	}
+	if alias, ok := versionAliases[s]; ok {
+		s = alias
+	}
+	return versionString(s)
+}
+
+// ParseVersion parses version. Any string is a valid version.
+func ParseVersion(version string) Version {
+	v := strings.ToLower(version)
+	root := &versionList{}
+	list := root
+	stack := []*versionList{root}
+	push := func() {
+		sub := &versionList{}
+		list.items = append(list.items, sub)
+		list = sub
+		stack = append(stack, sub)
+	}
+
+	isDigit := false
+	start := 0
+	for i := 0; i < len(v); i++ {
+		c := v[i]
+		switch {
+		case c == '.' || c == '-':
+			if i == start {
+				list.items = append(list.items, versionInt(""))
+			} else {
+				list.items = append(list.items, parseVersionItem(v[start:i], isDigit, false))
+			}
+			start = i + 1
+			if c == '-' {
+				push()
+			}
+		case c >= '0' && c <= '9':
+			if !isDigit && i > start {
+				list.items = append(list.items, parseVersionItem(v[start:i], false, true))
+				start = i
+				push()
+			}
+			isDigit = true
+		default:
+			if isDigit && i > start {
+				list.items = append(list.items, parseVersionItem(v[start:i], true, false))
+				start = i
+				push()
+			}
+			isDigit = false
+		}
+	}
+	if len(v) > start {
+		list.items = append(list.items, parseVersionItem(v[start:], isDigit, false))
+	}
+	for i := len(stack) - 1; i >= 0; i-- {
+		stack[i].normalize()
+	}
+	return Version{raw: version, items: root}
+}
+
+// Compare returns -1, 0 or 1 when v is older than, equal to or newer than
+// other.
+func (v Version) Compare(other Version) int {
+	return v.list().compare(other.list())
+}
+
+func (v Version) list() *versionList {
+	if v.items == nil {
+		return &versionList{}
+	}
+	return v.items
+}
+
+// Canonical returns the normalized form of v, identical for equal versions.
+func (v Version) Canonical() string {
+	return v.list().String()
+}
+
+func (v Version) String() string {
+	return v.raw
+}
+
+// CompareVersions compares two version strings with Maven ordering.
+func CompareVersions(a, b string) int {
+	return ParseVersion(a).Compare(ParseVersion(b))
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// VersionRange is a Maven version range such as [1.0,2.0), (,1.0] or the
+// union [1.0,1.2),[1.5,). A plain version is a soft requirement, which
+// Maven treats as a recommendation matching any version.
+type VersionRange struct {
+	raw          string
+	restrictions []versionRestriction
+}
+
+type versionRestriction struct {
+	lower, upper                   *Version
+	lowerInclusive, upperInclusive bool
+}
+
+// ParseVersionRange parses spec, failing on unbalanced brackets, bounds in
+// the wrong order or overlapping restrictions.
+func ParseVersionRange(spec string) (VersionRange, error) {
+	r := VersionRange{raw: spec}
+	s := strings.TrimSpace(spec)
+	if s == "" {
+		return r, fmt.Errorf("empty version range")
+	}
+	if !isVersionRange(s) {
+		if strings.ContainsAny(s, "[]()") {
+			return r, fmt.Errorf("invalid version range %q", spec)
+		}
+		return r, nil
+	}
+	for s != "" {
+		end := strings.IndexAny(s, "])")
+		if end < 0 {
+			return r, fmt.Errorf("unclosed version range %q", spec)
+		}
+		restriction, err := parseVersionRestriction(s[:end+1])
+		if err != nil {
+			return r, fmt.Errorf("%v in version range %q", err, spec)
+		}
+		if n := len(r.restrictions); n > 0 {
+			previous := r.restrictions[n-1]
+			if previous.upper == nil || restriction.lower == nil || previous.upper.Compare(*restriction.lower) > 0 {
+				return r, fmt.Errorf("overlapping restrictions in version range %q", spec)
+			}
+		}
+		r.restrictions = append(r.restrictions, restriction)
+		s = strings.TrimSpace(s[end+1:])
+		if strings.HasPrefix(s, ",") {
+			s = strings.TrimSpace(s[1:])
+			if s == "" {
+				return r, fmt.Errorf("trailing comma in version range %q", spec)
+			}
+		}
+		if s != "" && !isVersionRange(s) {
+			return r, fmt.Errorf("invalid version range %q", spec)
+		}
+	}
+	return r, nil
+}
+
+func parseVersionRestriction(s string) (versionRestriction, error) {
+	r := versionRestriction{lowerInclusive: s[0] == '[', upperInclusive: s[len(s)-1] == ']'}
+	inner := strings.TrimSpace(s[1 : len(s)-1])
+	if !strings.Contains(inner, ",") {
+		// [1.0] pins a single version
+		if !r.lowerInclusive || !r.upperInclusive || inner == "" {
+			return r, fmt.Errorf("single version %s must be inclusive", s)
+		}
+		v := ParseVersion(inner)
+		r.lower, r.upper = &v, &v
+		return r, nil
+	}
+	bounds := strings.Split(inner, ",")
+	if len(bounds) != 2 {
+		return r, fmt.Errorf("invalid restriction %s", s)
+	}
+	if lower := strings.TrimSpace(bounds[0]); lower != "" {
+		v := ParseVersion(lower)
+		r.lower = &v
+	}
+	if upper := strings.TrimSpace(bounds[1]); upper != "" {
+		v := ParseVersion(upper)
+		r.upper = &v
+	}
+	if r.lower != nil && r.upper != nil {
+		switch c := r.lower.Compare(*r.upper); {
+		case c > 0:
+			return r, fmt.Errorf("lower bound above upper bound in %s", s)
+		case c == 0 && (!r.lowerInclusive || !r.upperInclusive):
+			return r, fmt.Errorf("range %s cannot have identical boundaries", s)
+		}
+	}
+	return r, nil
+}
+
+// Contains reports whether version satisfies one of the restrictions of r.
+func (r VersionRange) Contains(version string) bool {
+	if len(r.restrictions) == 0 {
+		return true
+	}
+	v := ParseVersion(version)
+	for _, restriction := range r.restrictions {
+		if restriction.contains(v) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r versionRestriction) contains(v Version) bool {
+	if r.lower != nil {
+		c := v.Compare(*r.lower)
+		if c < 0 || c == 0 && !r.lowerInclusive {
+			return false
+		}
+	}
+	if r.upper != nil {
+		c := v.Compare(*r.upper)
+		if c > 0 || c == 0 && !r.upperInclusive {
+			return false
+		}
+	}
+	return true
+}
+
+// IsRange reports whether r restricts versions rather than recommending one.
+func (r VersionRange) IsRange() bool {
+	return len(r.restrictions) > 0
+}
+
+func (r VersionRange) String() string {
+	return r.raw
+}
+
+// isVersionRange reports whether s is written as a range rather than a
+// plain version.
+func isVersionRange(s string) bool {
+	return strings.HasPrefix(s, "[") || strings.HasPrefix(s, "(")
+}
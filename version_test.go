@@ -0,0 +1,60 @@
+package mvnparse
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareVersions(t *testing.T) {
+	ascending := []string{
+		"1-alpha-1", "1-alpha2", "1-beta-1", "1-m1", "1-rc1", "1-SNAPSHOT",
+		"1", "1-sp", "1-abc", "1-1", "1.0.1", "1.1", "1.2-jre", "1.10", "2.0.0-RC1",
+		"2", "2.0.1", "10", "99999999999999999999",
+	}
+	for i := 0; i < len(ascending)-1; i++ {
+		assert.Equal(t, -1, CompareVersions(ascending[i], ascending[i+1]), "%s < %s", ascending[i], ascending[i+1])
+		assert.Equal(t, 1, CompareVersions(ascending[i+1], ascending[i]), "%s > %s", ascending[i+1], ascending[i])
+	}
+	for _, equal := range [][2]string{
+		{"1", "1.0.0"},
+		{"1.0", "1-ga"},
+		{"1.0", "1.0.FINAL"},
+		{"1-cr1", "1-rc-1"},
+		{"1a1", "1-alpha-1"},
+		{"1.0-SNAPSHOT", "1-snapshot"},
+		{"01.2", "1.2"},
+	} {
+		assert.Equal(t, 0, CompareVersions(equal[0], equal[1]), "%s = %s", equal[0], equal[1])
+		assert.Equal(t, ParseVersion(equal[0]).Canonical(), ParseVersion(equal[1]).Canonical())
+	}
+	assert.Equal(t, "1-alpha-1", ParseVersion("1.0a1").Canonical())
+	assert.Equal(t, "1.0a1", ParseVersion("1.0a1").String())
+}
+
+func TestParseVersionRange(t *testing.T) {
+	for spec, cases := range map[string]map[string]bool{
+		"[1.0,2.0)":    {"0.9": false, "1.0": true, "1.5-SNAPSHOT": true, "2.0-alpha-1": true, "2.0": false},
+		"(,1.0]":       {"0.1": true, "1.0": true, "1.0.1": false},
+		"[1.0,1.0]":    {"1.0": true, "1.0.1": false},
+		"[1.2]":        {"1.2": true, "1.2.0": true, "1.2.1": false},
+		"(1.0,)":       {"1.0": false, "1.0.1": true, "99": true},
+		"[1,2),[3,4]":  {"1.9": true, "2.5": false, "4": true, "4.1": false},
+		"1.0":          {"0.1": true, "5": true},
+		" [ 1.0 , 2] ": {"2": true},
+	} {
+		r, err := ParseVersionRange(spec)
+		assert.NoError(t, err, spec)
+		for version, contained := range cases {
+			assert.Equal(t, contained, r.Contains(version), "%s in %s", version, spec)
+		}
+	}
+	r, _ := ParseVersionRange("[1.0,2.0)")
+	assert.True(t, r.IsRange())
+	assert.Equal(t, "[1.0,2.0)", r.String())
+
+	for _, spec := range []string{"", "[1.0", "(1.0)", "[2.0,1.0]", "[1,3),[2,4)", "[1,2),", "[1,2,3]", "1.0]", "(1.0,1.0)", "[1.0,1.0)", "(1.0,1.0]"} {
+		_, err := ParseVersionRange(spec)
+		assert.Error(t, err, spec)
+	}
+}
@@ -0,0 +1,191 @@
+package mvnparse
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// setText sets the text of the element at path, such as
+// /project/properties/revision, for the next Write. The caller updates the
+// model itself.
+func (pf *ProjectFile) setText(path, value string) {
+	if pf.edits == nil {
+		pf.edits = map[string]string{}
+	}
+	pf.edits[normalizeLocationPath(path)] = value
+}
+
+// Write saves the edits made by UpdateVersion, SetVersion and the release
+// methods to the file of the project. Only the text of the edited elements
+// changes: comments, namespaces, escaping and layout are kept byte for byte.
+// A missing element is added at the end of its parent.
+func (pf *ProjectFile) Write() error {
+	if len(pf.edits) == 0 {
+		return nil
+	}
+	info, err := os.Stat(pf.Path)
+	if err != nil {
+		return err
+	}
+	data, err := ioutil.ReadFile(pf.Path)
+	if err != nil {
+		return err
+	}
+	data, err = applyTextEdits(data, pf.edits)
+	if err != nil {
+		return fmt.Errorf("%s: %v", pf.Path, err)
+	}
+	if err := ioutil.WriteFile(pf.Path, data, info.Mode().Perm()); err != nil {
+		return err
+	}
+	pf.edits = nil
+	return nil
+}
+
+// elementSpan is where an element lies in a document: from start to end
+// for the whole element and from textStart to textEnd for its content.
+type elementSpan struct {
+	name                      string
+	start, textStart, textEnd int
+	end                       int
+	// firstChild is the start of the first child element, -1 when the
+	// element only holds text.
+	firstChild int
+}
+
+func (s elementSpan) selfClosing() bool {
+	return s.textStart == s.end
+}
+
+// elementSpans indexes the elements of data by the normalized paths of
+// Locations.
+func elementSpans(data []byte) (map[string]*elementSpan, error) {
+	type frame struct {
+		path   string
+		counts map[string]int
+		span   *elementSpan
+	}
+	spans := map[string]*elementSpan{}
+	stack := []*frame{{counts: map[string]int{}}}
+	d := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		offset := int(d.InputOffset())
+		tok, err := d.Token()
+		if err == io.EOF {
+			return spans, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			parent := stack[len(stack)-1]
+			if parent.span != nil && parent.span.firstChild < 0 {
+				parent.span.firstChild = offset
+			}
+			parent.counts[t.Name.Local]++
+			path := parent.path + "/" + t.Name.Local + "[" + strconv.Itoa(parent.counts[t.Name.Local]) + "]"
+			// the name as written, namespace prefix included
+			name := data[offset+1:]
+			name = name[:bytes.IndexAny(name, " \t\r\n/>")]
+			span := &elementSpan{name: string(name), start: offset, textStart: int(d.InputOffset()), firstChild: -1}
+			spans[path] = span
+			stack = append(stack, &frame{path: path, counts: map[string]int{}, span: span})
+		case xml.EndElement:
+			span := stack[len(stack)-1].span
+			span.end = int(d.InputOffset())
+			span.textEnd = offset
+			stack = stack[:len(stack)-1]
+		}
+	}
+}
+
+var textEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+type textEdit struct {
+	start, end int
+	text       string
+}
+
+// applyTextEdits replaces the text of the elements at the paths of edits,
+// leaving the rest of data untouched.
+func applyTextEdits(data []byte, edits map[string]string) ([]byte, error) {
+	spans, err := elementSpans(data)
+	if err != nil {
+		return nil, err
+	}
+	paths := make([]string, 0, len(edits))
+	for path := range edits {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	var changes []textEdit
+	for _, path := range paths {
+		text := textEscaper.Replace(edits[path])
+		span, ok := spans[path]
+		if !ok {
+			change, err := insertElement(data, spans, path, text)
+			if err != nil {
+				return nil, err
+			}
+			changes = append(changes, change)
+			continue
+		}
+		switch {
+		case span.firstChild >= 0:
+			return nil, fmt.Errorf("%s holds elements, not text", path)
+		case span.selfClosing():
+			changes = append(changes, textEdit{span.start, span.end, "<" + span.name + ">" + text + "</" + span.name + ">"})
+		default:
+			changes = append(changes, textEdit{span.textStart, span.textEnd, text})
+		}
+	}
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].start > changes[j].start
+	})
+	for _, c := range changes {
+		data = append(data[:c.start:c.start], append([]byte(c.text), data[c.end:]...)...)
+	}
+	return data, nil
+}
+
+// insertElement adds the element at path to the end of its parent, on a
+// line of its own indented like its siblings when the parent spans lines.
+func insertElement(data []byte, spans map[string]*elementSpan, path, text string) (textEdit, error) {
+	i := strings.LastIndex(path, "/")
+	name := path[i+1:]
+	if !strings.HasSuffix(name, "[1]") {
+		return textEdit{}, fmt.Errorf("no element %s", path)
+	}
+	name = strings.TrimSuffix(name, "[1]")
+	parent, ok := spans[path[:i]]
+	if !ok || parent.selfClosing() {
+		return textEdit{}, fmt.Errorf("no element %s to add %s to", path[:i], name)
+	}
+	element := "<" + name + ">" + text + "</" + name + ">"
+
+	lineStart := bytes.LastIndexByte(data[:parent.textEnd], '\n') + 1
+	indent := data[lineStart:parent.textEnd]
+	if lineStart == 0 || len(bytes.TrimLeft(indent, " \t")) > 0 {
+		return textEdit{parent.textEnd, parent.textEnd, element}, nil
+	}
+	newline := "\n"
+	if lineStart >= 2 && data[lineStart-2] == '\r' {
+		newline = "\r\n"
+	}
+	childIndent := string(indent) + "  "
+	if parent.firstChild >= 0 {
+		childStart := bytes.LastIndexByte(data[:parent.firstChild], '\n') + 1
+		if ws := data[childStart:parent.firstChild]; len(bytes.TrimLeft(ws, " \t")) == 0 {
+			childIndent = string(ws)
+		}
+	}
+	return textEdit{lineStart, lineStart, childIndent + element + newline}, nil
+}
@@ -0,0 +1,37 @@
+package mvnparse
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyTextEdits(t *testing.T) {
+	pom := `<project>
+    <version/>
+    <scm>
+        <url>https://example.com</url>
+    </scm>
+    <properties><a>1</a></properties>
+</project>`
+	data, err := applyTextEdits([]byte(pom), map[string]string{
+		"/project[1]/version[1]":         "1.0",
+		"/project[1]/scm[1]/tag[1]":      "v1 & v2",
+		"/project[1]/properties[1]/a[1]": "<2>",
+		"/project[1]/properties[1]/b[1]": "3",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, `<project>
+    <version>1.0</version>
+    <scm>
+        <url>https://example.com</url>
+        <tag>v1 &amp; v2</tag>
+    </scm>
+    <properties><a>&lt;2&gt;</a><b>3</b></properties>
+</project>`, string(data))
+
+	_, err = applyTextEdits([]byte(pom), map[string]string{"/project[1]/scm[1]": "x"})
+	assert.Error(t, err)
+	_, err = applyTextEdits([]byte(pom), map[string]string{"/project[1]/build[1]/finalName[1]": "x"})
+	assert.Error(t, err)
+}